@@ -1,6 +1,7 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -17,12 +18,84 @@ import (
 
 // State constants
 const (
-	stateMenu           = "menu"
-	stateCreateCalendar = "create_calendar"
-	stateSelectCalendar = "select_calendar"
-	stateViewCalendars  = "view_calendars"
-	stateEventDate      = "event_date"
-	stateEventName      = "event_name"
+	stateMenu             = "menu"
+	stateCreateCalendar   = "create_calendar"
+	stateSelectCalendar   = "select_calendar"
+	stateViewCalendars    = "view_calendars"
+	stateEventDate        = "event_date"
+	stateEventDateConfirm = "event_date_confirm"
+	stateEventEnd         = "event_end"
+	stateEventRepeat      = "event_repeat"
+	stateEventName        = "event_name"
+	stateEventTags        = "event_tags"
+	stateEventCategory    = "event_category"
+	stateEventTemplate    = "event_template"
+	stateEventField       = "event_field"
+	stateEventLocations   = "event_locations"
+	stateEventCharacters  = "event_characters"
+	stateEventLinks       = "event_links"
+	stateEventSecret      = "event_secret"
+	stateEventStatus      = "event_status"
+	stateEventCollision   = "event_collision"
+	stateTagFilter        = "tag_filter"
+	stateSearch           = "search"
+	stateSearchResults    = "search_results"
+	stateLookupCalendar   = "lookup_calendar"
+	stateLookupDate       = "lookup_date"
+	stateLookupResults    = "lookup_results"
+
+	stateCharacterCalendar = "character_calendar"
+	stateAddCharacter      = "add_character"
+	stateCharacters        = "characters"
+	stateCharacterAgeDate  = "character_age_date"
+
+	stateAddLocation = "add_location"
+	stateLocations   = "locations"
+
+	stateKnowledgeEvent      = "knowledge_event"
+	stateKnowledgeCharacter  = "knowledge_character"
+	stateKnowledgeDate       = "knowledge_date"
+	stateKnowledgeCharacters = "knowledge_characters"
+	stateKnowledgeAsOf       = "knowledge_as_of"
+
+	stateMigrateConfirm = "migrate_confirm"
+
+	stateImportPath    = "import_path"
+	stateImportConfirm = "import_confirm"
+
+	stateClockCalendar = "clock_calendar"
+	stateClockAdvance  = "clock_advance"
+	stateClockDigest   = "clock_digest"
+
+	stateSessionCalendar  = "session_calendar"
+	stateLogSession       = "log_session"
+	stateTimelineCalendar = "timeline_calendar"
+	stateTimeline         = "timeline"
+
+	stateCountdownCalendar = "countdown_calendar"
+	stateAddCountdown      = "add_countdown"
+	stateCountdowns        = "countdowns"
+
+	statePartyCalendar = "party_calendar"
+	stateAddParty      = "add_party"
+	stateParties       = "parties"
+	stateSelectParty   = "select_party"
+	stateCatchUpFirst  = "catch_up_first"
+	stateCatchUpSecond = "catch_up_second"
+
+	stateTravelCalendar = "travel_calendar"
+	stateTravel         = "travel"
+
+	stateEncounterTable    = "encounter_table"
+	stateEncounterCalendar = "encounter_calendar"
+	stateEncounter         = "encounter"
+
+	stateStatusEvent = "status_event"
+	stateStatusValue = "status_value"
+
+	stateHistory        = "history"
+	stateHistoryConfirm = "history_confirm"
+	stateReason         = "reason"
 )
 
 // AppModel represents the application state
@@ -44,7 +117,95 @@ type AppModel struct {
 	// Create Event fields
 	eventCalendarIndex int
 	eventData          config.Event
+	templates          []config.EventTemplate
+	templateFields     []config.TemplateField
+	templateFieldIndex int
 	eventDateStr       string
+
+	// Event lookup fields
+	lookupCalendarIndex int
+	lookupEvents        []config.Event
+
+	// Filter applied to every event listing
+	eventFilter commands.EventFilter
+
+	// Search fields
+	searchIndex *commands.SearchIndex
+
+	// Character fields
+	characterInput      config.Character
+	characterInputStage int
+	characters          []config.Character
+	characterIndex      int
+
+	// Location fields
+	locationInput      config.Location
+	locationInputStage int
+	locations          []config.Location
+
+	// Knowledge fields
+	knowledgeEvent     config.Event
+	knowledgeCharacter string
+
+	// Rows of the import awaiting confirmation
+	importRows []commands.ImportRow
+
+	// Calendar whose clock is being changed and what advancing it passed
+	clockCalendarIndex int
+	clockInputStage    int
+	clockInput         string // amount of time or date to advance to
+	clockDigest        []commands.DigestEntry
+
+	// Session fields
+	sessionInput      config.Session
+	sessionInputStage int
+	timeline          []commands.TimelineEntry
+
+	// Countdown fields
+	countdownInput      config.Countdown
+	countdownTarget     string
+	countdownInputStage int
+	countdowns          []commands.CountdownStatus
+
+	// Party fields. New events are attributed to the active party and
+	// dated from its clock.
+	parties         []config.Party
+	activeParty     string
+	partyInput      config.Party
+	partyInputStage int
+	catchUpParty    string
+
+	// Party whose clock is being changed, blank for a calendar's clock
+	clockParty string
+
+	// Travel calculator fields
+	travelCalendarIndex int
+	travelInputStage    int
+	travelDeparture     config.Event
+	travelPlan          commands.TravelPlan
+	travelResult        commands.TravelResult
+
+	// Random encounter fields
+	encounterTables        []config.EncounterTable
+	encounterTable         config.EncounterTable
+	encounterCalendarIndex int
+	encounterInputStage    int
+	encounterDate          config.Event
+	encounterTime          string
+	encounterLocation      string
+	encounterRoll          commands.EncounterRoll
+
+	// Event whose status is being set
+	statusEvent config.Event
+
+	// History fields, listed most recent first
+	history      []config.HistoryEntry
+	historySteps int  // operations to undo or redo
+	historyRedo  bool // redoing rather than undoing
+
+	// Operation waiting for its reason, and the last reason given
+	tracked *trackedAction
+	reason  string
 }
 
 // Start initializes and runs the application
@@ -73,6 +234,12 @@ func initialModel() AppModel {
 		statusMsg = "No existing configuration found. Starting fresh."
 	}
 
+	// Parties are shown in the header, so keep them at hand
+	parties, err := config.LoadParties()
+	if err != nil {
+		statusMsg = ui.RenderError(fmt.Sprintf("Error loading parties: %v", err))
+	}
+
 	// Create menu list
 	menuList := ui.NewMenuList(ui.MainMenuItems(), "Fantasy Calendar CLI", 0, 0)
 
@@ -80,6 +247,7 @@ func initialModel() AppModel {
 	return AppModel{
 		state:       stateMenu,
 		config:      cfg,
+		parties:     parties,
 		input:       ui.NewTextInput(""),
 		menuList:    menuList,
 		keymap:      DefaultKeyMap(),
@@ -99,9 +267,21 @@ func (m AppModel) View() string {
 	var content string
 
 	switch m.state {
-	case stateMenu, stateSelectCalendar, stateViewCalendars:
+	case stateMenu, stateSelectCalendar, stateViewCalendars, stateLookupCalendar, stateLookupResults,
+		stateSearchResults, stateCharacterCalendar, stateCharacters, stateLocations,
+		stateKnowledgeCharacters, stateEventTemplate, stateClockCalendar, stateClockDigest,
+		stateSessionCalendar, stateTimelineCalendar, stateTimeline, stateCountdownCalendar, stateCountdowns,
+		statePartyCalendar, stateParties, stateSelectParty, stateCatchUpFirst, stateCatchUpSecond,
+		stateTravelCalendar, stateEncounterTable, stateEncounterCalendar, stateHistory:
 		content = m.menuList.View()
-	case stateCreateCalendar, stateEventDate, stateEventName:
+	case stateCreateCalendar, stateEventDate, stateEventDateConfirm, stateEventEnd, stateEventRepeat, stateEventName,
+		stateEventTags, stateEventCategory, stateLookupDate, stateTagFilter, stateSearch,
+		stateAddCharacter, stateCharacterAgeDate, stateEventLocations, stateAddLocation,
+		stateEventCharacters, stateEventLinks, stateEventSecret, stateEventStatus, stateEventCollision, stateEventField, stateKnowledgeEvent, stateKnowledgeCharacter,
+		stateKnowledgeDate, stateKnowledgeAsOf, stateMigrateConfirm,
+		stateImportPath, stateImportConfirm, stateClockAdvance, stateLogSession,
+		stateAddCountdown, stateAddParty, stateTravel, stateEncounter, stateHistoryConfirm,
+		stateStatusEvent, stateStatusValue, stateReason:
 		var headerText string
 		switch m.state {
 		case stateCreateCalendar:
@@ -117,8 +297,155 @@ func (m AppModel) View() string {
 			}
 		case stateEventDate:
 			headerText = "Create Event - Enter Date"
+		case stateEventDateConfirm:
+			headerText = "Create Event - Confirm Date"
+		case stateEventEnd:
+			headerText = "Create Event - Enter End Date or Duration"
+		case stateEventRepeat:
+			headerText = "Create Event - Enter Repeat Rule"
 		case stateEventName:
 			headerText = "Create Event - Enter Name"
+		case stateEventTags:
+			headerText = "Create Event - Enter Tags"
+		case stateEventCategory:
+			headerText = "Create Event - Enter Category"
+		case stateEventField:
+			headerText = fmt.Sprintf("Create Event - %s", m.templateFields[m.templateFieldIndex].Name)
+		case stateEventLocations:
+			headerText = "Create Event - Enter Locations"
+		case stateEventCharacters:
+			headerText = "Create Event - Enter Characters"
+		case stateEventLinks:
+			headerText = "Create Event - Enter Links"
+		case stateEventSecret:
+			headerText = "Create Event - GM Only?"
+		case stateEventStatus:
+			headerText = "Create Event - Status"
+		case stateEventCollision:
+			headerText = "Create Event - File Already Exists"
+		case stateLookupDate:
+			headerText = "Events On Date - Enter Date"
+		case stateTagFilter:
+			headerText = "Filter By Tag"
+		case stateSearch:
+			headerText = "Search Events"
+		case stateAddCharacter:
+			switch m.characterInputStage {
+			case 1:
+				headerText = "Add Character - Name"
+			case 2:
+				headerText = "Add Character - Birth Date"
+			case 3:
+				headerText = "Add Character - Death Date"
+			case 4:
+				headerText = "Add Character - Species"
+			case 5:
+				headerText = "Add Character - Lifespan"
+			}
+		case stateCharacterAgeDate:
+			headerText = fmt.Sprintf("Age of %s - Enter Date", m.characters[m.characterIndex].Name)
+		case stateClockAdvance:
+			headerText = fmt.Sprintf("Campaign Clock - %s", m.config.Calendars[m.clockCalendarIndex].Name)
+			if m.clockParty != "" {
+				headerText = fmt.Sprintf("Party Clock - %s", m.clockParty)
+			}
+			if m.clockInputStage == 2 {
+				headerText += " - Reason"
+			}
+		case stateImportPath:
+			headerText = "Import Events - File"
+		case stateImportConfirm:
+			headerText = "Import Events - Confirm"
+		case stateMigrateConfirm:
+			headerText = fmt.Sprintf("Migrate Event Files - %s", commands.EventPathPattern(m.config))
+		case stateKnowledgeEvent:
+			headerText = "Record Knowledge - Event"
+		case stateKnowledgeCharacter:
+			headerText = "Record Knowledge - Character"
+		case stateKnowledgeDate:
+			headerText = "Record Knowledge - Date Learned"
+		case stateKnowledgeAsOf:
+			headerText = fmt.Sprintf("What %s Knows - Enter Date", m.characters[m.characterIndex].Name)
+		case stateAddLocation:
+			switch m.locationInputStage {
+			case 1:
+				headerText = "Add Location - Name"
+			case 2:
+				headerText = "Add Location - Kind"
+			case 3:
+				headerText = "Add Location - Enclosing Location"
+			}
+		case stateLogSession:
+			switch m.sessionInputStage {
+			case 1:
+				headerText = "Log Session - Number"
+			case 2:
+				headerText = "Log Session - Date Played"
+			case 3:
+				headerText = "Log Session - In-Game Start"
+			case 4:
+				headerText = "Log Session - In-Game End"
+			case 5:
+				headerText = "Log Session - Players"
+			}
+		case stateAddCountdown:
+			switch m.countdownInputStage {
+			case 1:
+				headerText = "Add Countdown - Name"
+			case 2:
+				headerText = "Add Countdown - Target"
+			case 3:
+				headerText = "Add Countdown - Warning Days"
+			}
+		case stateAddParty:
+			switch m.partyInputStage {
+			case 1:
+				headerText = "Add Party - Name"
+			case 2:
+				headerText = "Add Party - Current Date"
+			}
+		case stateTravel:
+			switch m.travelInputStage {
+			case 1:
+				headerText = "Travel - Departure"
+			case 2:
+				headerText = "Travel - Distance"
+			case 3:
+				headerText = "Travel - Mode"
+			case 4:
+				headerText = "Travel - Pace"
+			case 5:
+				headerText = "Travel - Hours Per Day"
+			case 6:
+				headerText = "Travel - Rest Days"
+			case 7:
+				headerText = "Travel - Log Journey"
+			case 8:
+				headerText = "Travel - Advance Clock"
+			}
+		case stateEncounter:
+			switch m.encounterInputStage {
+			case 1:
+				headerText = "Roll Encounter - Time of Day"
+			case 2:
+				headerText = "Roll Encounter - Location"
+			case 3:
+				headerText = "Roll Encounter - Seed"
+			case 4:
+				headerText = "Roll Encounter - Log Result"
+			}
+		case stateStatusEvent:
+			headerText = "Set Event Status - Event"
+		case stateStatusValue:
+			headerText = "Set Event Status - Status"
+		case stateHistoryConfirm:
+			if m.historyRedo {
+				headerText = "History - Confirm Redo"
+			} else {
+				headerText = "History - Confirm Undo"
+			}
+		case stateReason:
+			headerText = m.tracked.action + " - Reason"
 		}
 
 		header := ui.TitleStyle.Render(headerText)
@@ -141,11 +468,33 @@ func (m AppModel) View() string {
 
 // header returns the app header
 func (m AppModel) header() string {
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
-		ui.TitleStyle.Render("Fantasy Calendar CLI"),
-		"",
-	)
+	lines := []string{ui.TitleStyle.Render("Fantasy Calendar CLI")}
+	for _, cal := range m.config.Calendars {
+		if cal.CurrentDate == "" {
+			continue
+		}
+		today := cal.CurrentDate
+		if current, ok, err := commands.CurrentDate(cal, m.config.DaysInYear); err == nil && ok {
+			today = commands.DescribeDay(cal, current)
+		}
+		lines = append(lines, ui.RenderHighlight(fmt.Sprintf("Today in %s: %s", cal.Name, today)))
+	}
+	for _, party := range m.parties {
+		line := fmt.Sprintf("%s: %s", party.Name, m.describePartyDate(party))
+		if strings.EqualFold(party.Name, m.activeParty) {
+			line += " (playing)"
+		}
+		lines = append(lines, ui.RenderHighlight(line))
+	}
+	if m.eventFilter.PlayerView {
+		lines = append(lines, ui.RenderHighlight("PLAYER VIEW: GM-only content is hidden"))
+	}
+	if len(m.eventFilter.Tags) > 0 {
+		lines = append(lines, ui.RenderMuted("Filter: "+m.eventFilter.String()))
+	}
+	lines = append(lines, "")
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
 
 // footer returns the app footer with help and status messages
@@ -175,6 +524,43 @@ func (m AppModel) footer() string {
 	return footer.String()
 }
 
+// updateEventResults handles event listings, showing the details and
+// connections of the selected event
+func (m AppModel) updateEventResults(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if key.Matches(msg, m.keymap.Enter) {
+		index := m.menuList.Index()
+		if index < 0 || index >= len(m.lookupEvents) {
+			return m, cmd
+		}
+
+		events, err := commands.LoadEvents()
+		if err != nil {
+			m.statusMsg = ui.RenderError(err.Error())
+			return m, cmd
+		}
+
+		// Hide links to events the current view doesn't show
+		events = commands.FilterEvents(events, commands.EventFilter{PlayerView: m.eventFilter.PlayerView})
+
+		event := m.lookupEvents[index]
+		conns := commands.BuildLinkIndex(events).Connections(event)
+		m.statusMsg = commands.GetEventDetails(event, conns)
+	}
+
+	return m, cmd
+}
+
+// toMenu returns to the main menu
+func (m AppModel) toMenu() AppModel {
+	m.state = stateMenu
+	m.menuList.SetItems(ui.MainMenuItems())
+	m.menuList.Title = "Fantasy Calendar CLI"
+	return m
+}
+
 // Update handles messages and state transitions
 func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
@@ -207,10 +593,8 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, m.keymap.Back):
 			if m.state != stateMenu {
-				m.state = stateMenu
+				m = m.toMenu()
 				m.statusMsg = ""
-				m.menuList.SetItems(ui.MainMenuItems())
-				m.menuList.Title = "Fantasy Calendar CLI"
 				return m, nil
 			}
 		}
@@ -242,6 +626,125 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							m.statusMsg = ""
 						}
 
+					case "Events On Date":
+						if len(m.config.Calendars) == 0 {
+							m.statusMsg = ui.RenderError("No calendars available. Create a calendar first.")
+						} else {
+							m.state = stateLookupCalendar
+							m.menuList.SetItems(ui.CalendarListItems(m.config.Calendars))
+							m.menuList.Title = "Select Calendar"
+							m.statusMsg = ""
+						}
+
+					case "Search Events":
+						events, err := commands.LoadEvents()
+						if err != nil {
+							m.statusMsg = ui.RenderError(err.Error())
+							break
+						}
+						events = commands.FilterEvents(events, m.eventFilter)
+						m.searchIndex = commands.BuildSearchIndex(events)
+						m.state = stateSearch
+						m.input = ui.NewTextInput("Words plus optional calendar:, tag:, from: and to: constraints")
+						m.statusMsg = ui.RenderMuted(fmt.Sprintf("%d events indexed.", len(events)))
+
+					case "Toggle Player View":
+						m.eventFilter.PlayerView = !m.eventFilter.PlayerView
+						if m.eventFilter.PlayerView {
+							m.statusMsg = ui.RenderSuccess("Player view on: GM-only events and sections are hidden.")
+						} else {
+							m.statusMsg = ui.RenderSuccess("Player view off.")
+						}
+
+					case "Export Timeline":
+						events, err := commands.LoadEvents()
+						if err != nil {
+							m.statusMsg = ui.RenderError(err.Error())
+							break
+						}
+						path, err := commands.ExportTimeline(events, m.eventFilter)
+						if err != nil {
+							m.statusMsg = ui.RenderError(fmt.Sprintf("Failed to export timeline: %v", err))
+						} else {
+							m.statusMsg = ui.RenderSuccess(fmt.Sprintf("Timeline exported to %s", path))
+						}
+
+					case "Filter By Tag":
+						m.state = stateTagFilter
+						m.input = ui.NewTextInput("Comma-separated tags, or blank to clear")
+						m.input.SetValue(strings.Join(m.eventFilter.Tags, ", "))
+						m.statusMsg = ""
+
+					case "Add Character":
+						if len(m.config.Calendars) == 0 {
+							m.statusMsg = ui.RenderError("No calendars available. Create a calendar first.")
+						} else {
+							m = m.startAddCharacter()
+						}
+
+					case "Characters":
+						m = m.startCharacters()
+
+					case "Campaign Clock":
+						m = m.startClock()
+
+					case "Log Session":
+						m = m.startLogSession()
+
+					case "Session Timeline":
+						m = m.startTimeline()
+
+					case "Add Countdown":
+						m = m.startAddCountdown()
+
+					case "Countdowns":
+						m = m.startCountdowns()
+
+					case "Add Party":
+						m = m.startAddParty()
+
+					case "Parties":
+						m = m.startParties()
+
+					case "Select Party":
+						m = m.startSelectParty()
+
+					case "Party Catch-Up":
+						m = m.startCatchUp()
+
+					case "Travel Calculator":
+						m = m.startTravel()
+
+					case "Roll Encounter":
+						m = m.startEncounter()
+
+					case "Set Event Status":
+						m = m.startSetStatus()
+
+					case "History":
+						m = m.startHistory()
+
+					case "Check Consistency":
+						m = m.startConsistencyCheck()
+
+					case "Import Events":
+						m = m.startImport()
+
+					case "Migrate Event Files":
+						m = m.startMigrate()
+
+					case "Record Knowledge":
+						m = m.startRecordKnowledge()
+
+					case "Character Knowledge":
+						m = m.startCharacterKnowledge()
+
+					case "Add Location":
+						m = m.startAddLocation()
+
+					case "Locations":
+						m = m.startLocations()
+
 					case "View Calendars":
 						if len(m.config.Calendars) == 0 {
 							m.statusMsg = ui.RenderError("No calendars to view.")
@@ -287,7 +790,34 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						if cal.Name == item.Title {
 							m.eventCalendarIndex = idx
 							m.state = stateEventDate
+							m.input = ui.NewTextInput(eventDatePrompt)
+							// New events default to the campaign's current date,
+							// or the active party's
+							m.input.SetValue(cal.CurrentDate)
+							if party, ok := m.activePartyIn(cal.Name); ok {
+								m.input.SetValue(party.CurrentDate)
+							}
+							m.statusMsg = ""
+							break
+						}
+					}
+				}
+			}
+
+		case stateLookupCalendar:
+			// Handle calendar selection for date lookups
+			m.menuList, cmd = m.menuList.Update(msg)
+			cmds = append(cmds, cmd)
+
+			if key.Matches(msg, m.keymap.Enter) {
+				item, ok := m.menuList.SelectedItem().(ui.Item)
+				if ok {
+					for idx, cal := range m.config.Calendars {
+						if cal.Name == item.Title {
+							m.lookupCalendarIndex = idx
+							m.state = stateLookupDate
 							m.input = ui.NewTextInput("Format: AAYYYY-MM-DD (e.g., AB0001-01-01)")
+							m.input.SetValue(cal.CurrentDate)
 							m.statusMsg = ""
 							break
 						}
@@ -295,6 +825,251 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
+		case stateLookupDate:
+			m.input, cmd = m.input.Update(msg)
+			cmds = append(cmds, cmd)
+
+			if key.Matches(msg, m.keymap.Enter) {
+				cal := m.config.Calendars[m.lookupCalendarIndex]
+				date, err := commands.ValidateEventDate(
+					strings.TrimSpace(m.input.Value()), cal, m.config.DaysInYear)
+				if err != nil {
+					m.statusMsg = ui.RenderError(err.Error())
+					return m, nil
+				}
+
+				events, err := commands.LoadEvents()
+				if err != nil {
+					m.statusMsg = ui.RenderError(err.Error())
+					return m, nil
+				}
+
+				events = commands.FilterEvents(events, m.eventFilter)
+				m.lookupEvents = commands.EventsOnDay(events, cal, m.config.DaysInYear, date.DaysSinceZero)
+				if len(m.lookupEvents) == 0 {
+					m.statusMsg = ui.RenderMuted(fmt.Sprintf("No events on %s.", date.DateString()))
+					return m, nil
+				}
+
+				m.state = stateLookupResults
+				m.menuList.SetItems(ui.EventListItems(m.lookupEvents))
+				m.menuList.Title = fmt.Sprintf("Events On %s", date.DateString())
+				m.statusMsg = aliveSummary(cal, m.config.DaysInYear, date.DaysSinceZero)
+			}
+
+		case stateLookupResults, stateSearchResults:
+			m, cmd = m.updateEventResults(msg)
+			cmds = append(cmds, cmd)
+
+		case stateSearch:
+			m.input, cmd = m.input.Update(msg)
+			cmds = append(cmds, cmd)
+
+			if key.Matches(msg, m.keymap.Enter) {
+				query, err := commands.ParseSearchQuery(m.input.Value(), m.config)
+				if err != nil {
+					m.statusMsg = ui.RenderError(err.Error())
+					return m, nil
+				}
+
+				results := m.searchIndex.Search(query)
+				if len(results) == 0 {
+					m.statusMsg = ui.RenderMuted("No matching events.")
+					return m, nil
+				}
+
+				highlight := func(s string) string { return ui.RenderHighlight(s) }
+				events := make([]config.Event, len(results))
+				snippets := make([]string, len(results))
+				for i, result := range results {
+					events[i] = result.Event
+					if len(query.Terms) > 0 {
+						snippets[i] = commands.Snippet(result.Event.Body, query.Terms, highlight)
+					}
+				}
+
+				m.lookupEvents = events
+				m.state = stateSearchResults
+				m.menuList.SetItems(ui.SearchResultItems(events, snippets))
+				m.menuList.Title = fmt.Sprintf("Search Results (%d)", len(results))
+				m.statusMsg = ""
+			}
+
+		case stateCharacterCalendar:
+			m, cmd = m.updateCharacterCalendar(msg)
+			cmds = append(cmds, cmd)
+
+		case stateAddCharacter:
+			m, cmd = m.updateAddCharacter(msg)
+			cmds = append(cmds, cmd)
+
+		case stateCharacters:
+			m, cmd = m.updateCharacters(msg)
+			cmds = append(cmds, cmd)
+
+		case stateCharacterAgeDate:
+			m, cmd = m.updateCharacterAgeDate(msg)
+			cmds = append(cmds, cmd)
+
+		case stateMigrateConfirm:
+			m, cmd = m.updateMigrateConfirm(msg)
+			cmds = append(cmds, cmd)
+
+		case stateClockCalendar:
+			m, cmd = m.updateClockCalendar(msg)
+			cmds = append(cmds, cmd)
+
+		case stateClockAdvance:
+			m, cmd = m.updateClockAdvance(msg)
+			cmds = append(cmds, cmd)
+
+		case stateClockDigest:
+			m, cmd = m.updateClockDigest(msg)
+			cmds = append(cmds, cmd)
+
+		case stateSessionCalendar:
+			m, cmd = m.updateSessionCalendar(msg)
+			cmds = append(cmds, cmd)
+
+		case stateLogSession:
+			m, cmd = m.updateLogSession(msg)
+			cmds = append(cmds, cmd)
+
+		case stateTimelineCalendar:
+			m, cmd = m.updateTimelineCalendar(msg)
+			cmds = append(cmds, cmd)
+
+		case stateTimeline:
+			m, cmd = m.updateTimeline(msg)
+			cmds = append(cmds, cmd)
+
+		case stateCountdownCalendar:
+			m, cmd = m.updateCountdownCalendar(msg)
+			cmds = append(cmds, cmd)
+
+		case stateAddCountdown:
+			m, cmd = m.updateAddCountdown(msg)
+			cmds = append(cmds, cmd)
+
+		case stateCountdowns:
+			m, cmd = m.updateCountdowns(msg)
+			cmds = append(cmds, cmd)
+
+		case statePartyCalendar:
+			m, cmd = m.updatePartyCalendar(msg)
+			cmds = append(cmds, cmd)
+
+		case stateAddParty:
+			m, cmd = m.updateAddParty(msg)
+			cmds = append(cmds, cmd)
+
+		case stateParties:
+			m, cmd = m.updateParties(msg)
+			cmds = append(cmds, cmd)
+
+		case stateSelectParty:
+			m, cmd = m.updateSelectParty(msg)
+			cmds = append(cmds, cmd)
+
+		case stateCatchUpFirst:
+			m, cmd = m.updateCatchUpFirst(msg)
+			cmds = append(cmds, cmd)
+
+		case stateCatchUpSecond:
+			m, cmd = m.updateCatchUpSecond(msg)
+			cmds = append(cmds, cmd)
+
+		case stateTravelCalendar:
+			m, cmd = m.updateTravelCalendar(msg)
+			cmds = append(cmds, cmd)
+
+		case stateTravel:
+			m, cmd = m.updateTravel(msg)
+			cmds = append(cmds, cmd)
+
+		case stateEncounterTable:
+			m, cmd = m.updateEncounterTable(msg)
+			cmds = append(cmds, cmd)
+
+		case stateEncounterCalendar:
+			m, cmd = m.updateEncounterCalendar(msg)
+			cmds = append(cmds, cmd)
+
+		case stateEncounter:
+			m, cmd = m.updateEncounter(msg)
+			cmds = append(cmds, cmd)
+
+		case stateStatusEvent:
+			m, cmd = m.updateStatusEvent(msg)
+			cmds = append(cmds, cmd)
+
+		case stateStatusValue:
+			m, cmd = m.updateStatusValue(msg)
+			cmds = append(cmds, cmd)
+
+		case stateHistory:
+			m, cmd = m.updateHistory(msg)
+			cmds = append(cmds, cmd)
+
+		case stateReason:
+			m, cmd = m.updateReason(msg)
+			cmds = append(cmds, cmd)
+
+		case stateHistoryConfirm:
+			m, cmd = m.updateHistoryConfirm(msg)
+			cmds = append(cmds, cmd)
+
+		case stateImportPath:
+			m, cmd = m.updateImportPath(msg)
+			cmds = append(cmds, cmd)
+
+		case stateImportConfirm:
+			m, cmd = m.updateImportConfirm(msg)
+			cmds = append(cmds, cmd)
+
+		case stateKnowledgeEvent:
+			m, cmd = m.updateKnowledgeEvent(msg)
+			cmds = append(cmds, cmd)
+
+		case stateKnowledgeCharacter:
+			m, cmd = m.updateKnowledgeCharacter(msg)
+			cmds = append(cmds, cmd)
+
+		case stateKnowledgeDate:
+			m, cmd = m.updateKnowledgeDate(msg)
+			cmds = append(cmds, cmd)
+
+		case stateKnowledgeCharacters:
+			m, cmd = m.updateKnowledgeCharacters(msg)
+			cmds = append(cmds, cmd)
+
+		case stateKnowledgeAsOf:
+			m, cmd = m.updateKnowledgeAsOf(msg)
+			cmds = append(cmds, cmd)
+
+		case stateAddLocation:
+			m, cmd = m.updateAddLocation(msg)
+			cmds = append(cmds, cmd)
+
+		case stateLocations:
+			m, cmd = m.updateLocations(msg)
+			cmds = append(cmds, cmd)
+
+		case stateTagFilter:
+			m.input, cmd = m.input.Update(msg)
+			cmds = append(cmds, cmd)
+
+			if key.Matches(msg, m.keymap.Enter) {
+				m.eventFilter.Tags = commands.ParseTags(m.input.Value())
+				if len(m.eventFilter.Tags) == 0 {
+					m.statusMsg = ui.RenderSuccess("Tag filter cleared.")
+				} else {
+					m.statusMsg = ui.RenderSuccess("Filtering events by tags: " + strings.Join(m.eventFilter.Tags, ", "))
+				}
+
+				m = m.toMenu()
+			}
+
 		case stateCreateCalendar:
 			// Handle calendar creation flow (multi-step)
 			m.input, cmd = m.input.Update(msg)
@@ -341,44 +1116,77 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.calendarInput.TotalYears = totalYears
 
 					// Create the calendar
-					if err := commands.CreateCalendar(&m.config, m.calendarInput); err != nil {
-						m.statusMsg = ui.RenderError(fmt.Sprintf("Failed to create calendar: %v", err))
-					} else {
-						m.statusMsg = ui.RenderSuccess("Calendar created successfully!")
+					cfg := m.config
+					run := func() error {
+						return commands.CreateCalendar(&cfg, m.calendarInput)
 					}
+					m = m.track("Create calendar "+m.calendarInput.Name, run, func(m AppModel, err error) AppModel {
+						if err != nil {
+							m.statusMsg = ui.RenderError(fmt.Sprintf("Failed to create calendar: %v", err))
+						} else {
+							m.config = cfg
+							m.statusMsg = ui.RenderSuccess("Calendar created successfully!")
+						}
 
-					// Reset and return to main menu
-					m.state = stateMenu
-					m.calendarInputStage = 0
-					m.menuList.SetItems(ui.MainMenuItems())
-					m.menuList.Title = "Fantasy Calendar CLI"
+						// Reset and return to main menu
+						m.calendarInputStage = 0
+						m = m.toMenu()
+						return m
+					})
 				}
 			}
 
 		case stateEventDate:
+			m, cmd = m.updateEventDate(msg)
+			cmds = append(cmds, cmd)
+
+		case stateEventDateConfirm:
+			m, cmd = m.updateEventDateConfirm(msg)
+			cmds = append(cmds, cmd)
+
+		case stateEventEnd:
 			m.input, cmd = m.input.Update(msg)
 			cmds = append(cmds, cmd)
 
 			if key.Matches(msg, m.keymap.Enter) {
-				m.eventDateStr = strings.TrimSpace(m.input.Value())
+				if err := commands.SetEventEnd(
+					&m.eventData,
+					m.input.Value(),
+					m.config.Calendars[m.eventCalendarIndex],
+					m.config.DaysInYear); err != nil {
+					m.statusMsg = ui.RenderError(err.Error())
+					return m, nil
+				}
+
+				m.state = stateEventRepeat
+				m.input = ui.NewTextInput("e.g. every 90 days, monthly on day 1, annually on 03-15, or blank")
+				if m.eventData.IsRanged() {
+					m.statusMsg = fmt.Sprintf("Event dates: %s to %s (%d days)",
+						m.eventData.DateString(), m.eventData.EndDateString(), m.eventData.Length())
+				}
+			}
+
+		case stateEventRepeat:
+			m.input, cmd = m.input.Update(msg)
+			cmds = append(cmds, cmd)
 
-				// Validate the date format
-				eventData, err := commands.ValidateEventDate(
-					m.eventDateStr,
+			if key.Matches(msg, m.keymap.Enter) {
+				rule, err := commands.ParseRecurrence(
+					m.input.Value(),
+					m.eventData,
 					m.config.Calendars[m.eventCalendarIndex],
 					m.config.DaysInYear)
-
 				if err != nil {
 					m.statusMsg = ui.RenderError(err.Error())
 					return m, nil
 				}
 
-				// Store the event data and move to name input
-				m.eventData = eventData
+				m.eventData.Recurrence = rule
 				m.state = stateEventName
 				m.input = ui.NewTextInput("Enter event name")
-				m.statusMsg = fmt.Sprintf("Event date: %s (Days since 0: %d)",
-					m.eventDateStr, eventData.DaysSinceZero)
+				if rule != nil {
+					m.statusMsg = fmt.Sprintf("Repeats %s", rule)
+				}
 			}
 
 		case stateEventName:
@@ -393,21 +1201,189 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 
-				// Update event name
+				// Update event name and move to tags input
 				m.eventData.Name = eventName
+				m.state = stateEventTags
+				m.input = ui.NewTextInput("Comma-separated tags (e.g. war, north), or blank")
+			}
+
+		case stateEventTags:
+			m.input, cmd = m.input.Update(msg)
+			cmds = append(cmds, cmd)
+
+			if key.Matches(msg, m.keymap.Enter) {
+				m.eventData.Tags = commands.ParseTags(m.input.Value())
+				m.state = stateEventCategory
+				m.input = ui.NewTextInput("Category (battle, birth, death, session, rumour...), or blank")
+			}
+
+		case stateEventCategory:
+			m.input, cmd = m.input.Update(msg)
+			cmds = append(cmds, cmd)
+
+			if key.Matches(msg, m.keymap.Enter) {
+				m.eventData.Category = commands.NormalizeCategory(m.input.Value())
+				m = m.startEventTemplate()
+			}
+
+		case stateEventTemplate:
+			m, cmd = m.updateEventTemplate(msg)
+			cmds = append(cmds, cmd)
+
+		case stateEventField:
+			m, cmd = m.updateEventField(msg)
+			cmds = append(cmds, cmd)
+
+		case stateEventLocations:
+			m.input, cmd = m.input.Update(msg)
+			cmds = append(cmds, cmd)
+
+			if key.Matches(msg, m.keymap.Enter) {
+				locations, err := config.LoadLocations()
+				if err != nil {
+					m.statusMsg = ui.RenderError(err.Error())
+					return m, nil
+				}
+
+				names, err := commands.ParseLocations(m.input.Value(), locations)
+				if err != nil {
+					m.statusMsg = ui.RenderError(err.Error())
+					return m, nil
+				}
+				m.eventData.Locations = names
+				m.state = stateEventCharacters
+				m.input = ui.NewTextInput("Comma-separated names of characters involved, or blank")
+				m.statusMsg = ""
+			}
+
+		case stateEventCharacters:
+			m.input, cmd = m.input.Update(msg)
+			cmds = append(cmds, cmd)
+
+			if key.Matches(msg, m.keymap.Enter) {
+				characters, err := config.LoadCharacters()
+				if err != nil {
+					m.statusMsg = ui.RenderError(err.Error())
+					return m, nil
+				}
+
+				names, err := commands.ParseCharacters(m.input.Value(), characters)
+				if err != nil {
+					m.statusMsg = ui.RenderError(err.Error())
+					return m, nil
+				}
+				m.eventData.Characters = names
+				m.state = stateEventLinks
+				m.input = ui.NewTextInput("e.g. followed:The Sundering; concurrent-with:1a2b3c4d, or blank")
+				m.statusMsg = ui.RenderMuted("Relations: " + strings.Join(commands.Relations, ", "))
+			}
+
+		case stateEventLinks:
+			m.input, cmd = m.input.Update(msg)
+			cmds = append(cmds, cmd)
+
+			if key.Matches(msg, m.keymap.Enter) {
+				events, err := commands.LoadEvents()
+				if err != nil {
+					m.statusMsg = ui.RenderError(err.Error())
+					return m, nil
+				}
+
+				links, err := commands.ParseLinks(m.input.Value(), events)
+				if err != nil {
+					m.statusMsg = ui.RenderError(err.Error())
+					return m, nil
+				}
+				m.eventData.Links = links
+				m.state = stateEventSecret
+				m.input = ui.NewTextInput("Hide the whole event from players? (y/N)")
+				m.statusMsg = ""
+			}
+
+		case stateEventSecret:
+			m.input, cmd = m.input.Update(msg)
+			cmds = append(cmds, cmd)
+
+			if key.Matches(msg, m.keymap.Enter) {
+				answer := strings.ToLower(strings.TrimSpace(m.input.Value()))
+				m.eventData.GMOnly = answer == "y" || answer == "yes"
+				m.state = stateEventStatus
+				m.input = ui.NewTextInput(statusPrompt)
+				m.statusMsg = ""
+			}
+
+		case stateEventStatus:
+			m.input, cmd = m.input.Update(msg)
+			cmds = append(cmds, cmd)
+
+			if key.Matches(msg, m.keymap.Enter) {
+				status, err := commands.ParseStatus(m.input.Value())
+				if err != nil {
+					m.statusMsg = ui.RenderError(err.Error())
+					return m, nil
+				}
+				m.eventData.Status = status
 
 				// Create the event
 				cal := m.config.Calendars[m.eventCalendarIndex]
-				if err := commands.CreateEvent(cal, m.eventData); err != nil {
-					m.statusMsg = ui.RenderError(fmt.Sprintf("Failed to create event: %v", err))
-				} else {
-					m.statusMsg = ui.RenderSuccess(fmt.Sprintf("Event '%s' created successfully!", eventName))
+				run := func() error {
+					return commands.CreateEvent(m.config, cal, m.eventData)
 				}
+				m = m.track("Create event "+m.eventData.Name, run, func(m AppModel, err error) AppModel {
+					if errors.Is(err, commands.ErrEventExists) {
+						m.state = stateEventCollision
+						m.input = ui.NewTextInput("(m)erge, (r)ename or (C)ancel")
+						m.statusMsg = ui.RenderError(err.Error())
+						return m
+					}
+					if err != nil {
+						m.statusMsg = ui.RenderError(fmt.Sprintf("Failed to create event: %v", err))
+					} else {
+						m.statusMsg = ui.RenderSuccess(fmt.Sprintf("Event '%s' created successfully!", m.eventData.Name))
+					}
+
+					// Reset and return to main menu
+					m = m.toMenu()
+					return m
+				})
+			}
 
-				// Reset and return to main menu
-				m.state = stateMenu
-				m.menuList.SetItems(ui.MainMenuItems())
-				m.menuList.Title = "Fantasy Calendar CLI"
+		case stateEventCollision:
+			m.input, cmd = m.input.Update(msg)
+			cmds = append(cmds, cmd)
+
+			if key.Matches(msg, m.keymap.Enter) {
+				cal := m.config.Calendars[m.eventCalendarIndex]
+
+				choice := strings.ToLower(strings.TrimSpace(m.input.Value()))
+				if choice == "" {
+					choice = "c"
+				}
+
+				// The reason given for creating the event carries over
+				done := func(success string) func(AppModel, error) AppModel {
+					return func(m AppModel, err error) AppModel {
+						if err != nil {
+							m.statusMsg = ui.RenderError(fmt.Sprintf("Failed to create event: %v", err))
+						} else {
+							m.statusMsg = ui.RenderSuccess(success)
+						}
+						return m.toMenu()
+					}
+				}
+				switch choice[:1] {
+				case "m":
+					m = m.retrack("Merge event "+m.eventData.Name, func() error {
+						return commands.MergeEvent(m.config, m.eventData)
+					}, done(fmt.Sprintf("Event '%s' merged into the existing event.", m.eventData.Name)))
+				case "r":
+					m = m.retrack("Create event "+m.eventData.Name, func() error {
+						return commands.CreateEventRenamed(m.config, cal, m.eventData)
+					}, done(fmt.Sprintf("Event '%s' created under a new file name.", m.eventData.Name)))
+				default:
+					m.statusMsg = ui.RenderMuted("Event not created.")
+					m = m.toMenu()
+				}
 			}
 		}
 	}
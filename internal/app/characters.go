@@ -0,0 +1,217 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sksmith/gmcli/internal/commands"
+	"github.com/sksmith/gmcli/internal/config"
+	"github.com/sksmith/gmcli/internal/ui"
+)
+
+// startAddCharacter begins the character creation flow, asking for a
+// calendar first when there is more than one
+func (m AppModel) startAddCharacter() AppModel {
+	m.characterInput = config.Character{}
+	m.statusMsg = ""
+
+	if len(m.config.Calendars) == 1 {
+		m.characterInput.Calendar = m.config.Calendars[0].Name
+		m.state = stateAddCharacter
+		m.characterInputStage = 1
+		m.input = ui.NewTextInput("Enter character name")
+		return m
+	}
+
+	m.state = stateCharacterCalendar
+	m.menuList.SetItems(ui.CalendarListItems(m.config.Calendars))
+	m.menuList.Title = "Select Calendar"
+	return m
+}
+
+// updateCharacterCalendar handles calendar selection for a new character
+func (m AppModel) updateCharacterCalendar(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if key.Matches(msg, m.keymap.Enter) {
+		if item, ok := m.menuList.SelectedItem().(ui.Item); ok {
+			m.characterInput.Calendar = item.Title
+			m.state = stateAddCharacter
+			m.characterInputStage = 1
+			m.input = ui.NewTextInput("Enter character name")
+		}
+	}
+
+	return m, cmd
+}
+
+// updateAddCharacter handles the multi-step character creation flow
+func (m AppModel) updateAddCharacter(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	input := strings.TrimSpace(m.input.Value())
+	cal, err := commands.FindCalendar(m.config, m.characterInput.Calendar)
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+
+	switch m.characterInputStage {
+	case 1: // Name
+		if input == "" {
+			m.statusMsg = ui.RenderError("character name cannot be empty")
+			return m, nil
+		}
+		m.characterInput.Name = input
+		m.input = ui.NewTextInput("Birth date (AAYYYY-MM-DD)")
+		m.characterInputStage = 2
+
+	case 2: // Birth date
+		if _, err := commands.ValidateEventDate(input, cal, m.config.DaysInYear); err != nil {
+			m.statusMsg = ui.RenderError(err.Error())
+			return m, nil
+		}
+		m.characterInput.Born = input
+		m.input = ui.NewTextInput("Death date (AAYYYY-MM-DD), or blank if alive")
+		m.characterInputStage = 3
+
+	case 3: // Death date
+		if input != "" {
+			if _, err := commands.ValidateEventDate(input, cal, m.config.DaysInYear); err != nil {
+				m.statusMsg = ui.RenderError(err.Error())
+				return m, nil
+			}
+		}
+		m.characterInput.Died = input
+		m.input = ui.NewTextInput("Species (e.g. human, elf), or blank")
+		m.characterInputStage = 4
+
+	case 4: // Species
+		m.characterInput.Species = input
+		m.input = ui.NewTextInput("Species lifespan in years, or blank")
+		m.characterInputStage = 5
+
+	case 5: // Lifespan
+		lifespan, err := commands.ValidateLifespan(input)
+		if err != nil {
+			m.statusMsg = ui.RenderError(err.Error())
+			return m, nil
+		}
+		m.characterInput.Lifespan = lifespan
+
+		run := func() error {
+			return commands.CreateCharacter(m.config, m.characterInput)
+		}
+		return m.track("Add character "+m.characterInput.Name, run, func(m AppModel, err error) AppModel {
+			if err != nil {
+				m.statusMsg = ui.RenderError(fmt.Sprintf("Failed to add character: %v", err))
+			} else {
+				m.statusMsg = ui.RenderSuccess(fmt.Sprintf("Character '%s' added!", m.characterInput.Name))
+			}
+
+			m.characterInputStage = 0
+			m = m.toMenu()
+			return m
+		}), nil
+	}
+
+	m.statusMsg = ""
+	return m, cmd
+}
+
+// startCharacters shows the character registry
+func (m AppModel) startCharacters() AppModel {
+	characters, err := config.LoadCharacters()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m
+	}
+	if len(characters) == 0 {
+		m.statusMsg = ui.RenderError("No characters yet. Add a character first.")
+		return m
+	}
+
+	m.characters = characters
+	m.state = stateCharacters
+	m.menuList.SetItems(ui.CharacterListItems(characters))
+	m.menuList.Title = "Characters"
+	m.statusMsg = ui.RenderMuted("Select a character to look up their age on a date.")
+	return m
+}
+
+// updateCharacters handles selecting a character for an age lookup
+func (m AppModel) updateCharacters(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if key.Matches(msg, m.keymap.Enter) {
+		index := m.menuList.Index()
+		if index >= 0 && index < len(m.characters) {
+			m.characterIndex = index
+			m.state = stateCharacterAgeDate
+			m.input = ui.NewTextInput("Date (AAYYYY-MM-DD)")
+			m.statusMsg = ""
+		}
+	}
+
+	return m, cmd
+}
+
+// updateCharacterAgeDate computes the selected character's age on a date
+func (m AppModel) updateCharacterAgeDate(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	ch := m.characters[m.characterIndex]
+	cal, err := commands.FindCalendar(m.config, ch.Calendar)
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+
+	date, err := commands.ValidateEventDate(strings.TrimSpace(m.input.Value()), cal, m.config.DaysInYear)
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+
+	age, err := commands.AgeOn(ch, cal, m.config.DaysInYear, date.DaysSinceZero)
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+
+	m.statusMsg = fmt.Sprintf("%s on %s: %s", ch.Name, date.DateString(), age.Status())
+	return m, cmd
+}
+
+// aliveSummary lists the characters alive on a day with their ages
+func aliveSummary(cal config.Calendar, daysInYear int, day int) string {
+	characters, err := config.LoadCharacters()
+	if err != nil {
+		return ui.RenderError(err.Error())
+	}
+
+	alive := commands.CharactersAliveOn(characters, cal, daysInYear, day)
+	if len(alive) == 0 {
+		return ""
+	}
+
+	names := make([]string, len(alive))
+	for i, ch := range alive {
+		names[i] = fmt.Sprintf("%s (%d)", ch.Name, ch.Age)
+	}
+	return "Alive: " + strings.Join(names, ", ")
+}
@@ -0,0 +1,212 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sksmith/gmcli/internal/commands"
+	"github.com/sksmith/gmcli/internal/config"
+	"github.com/sksmith/gmcli/internal/ui"
+)
+
+const clockPrompt = "Advance by (3 days, 2 weeks, 1 month) or enter a date"
+
+// startClock lists the calendars whose clock can be viewed or advanced
+func (m AppModel) startClock() AppModel {
+	if len(m.config.Calendars) == 0 {
+		m.statusMsg = ui.RenderError("No calendars available. Create a calendar first.")
+		return m
+	}
+
+	m.state = stateClockCalendar
+	m.menuList.SetItems(ui.CalendarListItems(m.config.Calendars))
+	m.menuList.Title = "Select Calendar"
+	m.statusMsg = ""
+	return m
+}
+
+// updateClockCalendar shows the selected calendar's current date and asks
+// how far to move it
+func (m AppModel) updateClockCalendar(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+	item, ok := m.menuList.SelectedItem().(ui.Item)
+	if !ok {
+		return m, cmd
+	}
+
+	for idx, cal := range m.config.Calendars {
+		if cal.Name != item.Title {
+			continue
+		}
+		m.clockCalendarIndex = idx
+		m.clockParty = ""
+		m.clockInputStage = 1
+		m.state = stateClockAdvance
+		m.input = ui.NewTextInput(clockPrompt)
+
+		current, ok, err := commands.CurrentDate(cal, m.config.DaysInYear)
+		switch {
+		case err != nil:
+			m.statusMsg = ui.RenderError(err.Error())
+		case ok:
+			m.statusMsg = ui.RenderHighlight("Current date: " + commands.DescribeDay(cal, current))
+		default:
+			m.statusMsg = ui.RenderMuted("The clock isn't set yet. Enter a date to start it.")
+		}
+		break
+	}
+	return m, cmd
+}
+
+// updateClockAdvance asks how far to move the clock and why, then moves
+// it by the entered amount of time or to the entered date
+func (m AppModel) updateClockAdvance(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	if m.clockInputStage == 1 {
+		m.clockInput = m.input.Value()
+		m.input = ui.NewTextInput("Reason for the change, or blank")
+		m.clockInputStage = 2
+		return m, cmd
+	}
+	return m.advanceClock(m.clockInput, m.input.Value()), cmd
+}
+
+// advanceClock moves the clock of the selected calendar or party, marks
+// the scheduled events it passed as occurred and lists deadline warnings
+// and everything passed on the way. The change is recorded in the history
+// with the reason given.
+func (m AppModel) advanceClock(input, reason string) AppModel {
+	events, err := commands.LoadEvents()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m
+	}
+	op, err := commands.BeginOperation()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m
+	}
+
+	cal := m.config.Calendars[m.clockCalendarIndex]
+	name := cal.Name
+	var previous, date config.Event
+	if m.clockParty != "" {
+		name = m.clockParty
+		previous, date, err = commands.AdvanceParty(m.config, m.clockParty, input, events)
+		m = m.reloadParties()
+	} else {
+		previous, date, err = commands.AdvanceClock(&m.config, m.clockCalendarIndex, input, events)
+	}
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		if m.state == stateClockAdvance {
+			// Ask again for the amount rather than the reason
+			m.input = ui.NewTextInput(clockPrompt)
+			m.input.SetValue(m.clockInput)
+			m.clockInputStage = 1
+		}
+		return m
+	}
+
+	summary := fmt.Sprintf("%s: %s → %s (%+d days)",
+		name, previous.DateString(), commands.DescribeDay(cal, date),
+		date.DaysSinceZero-previous.DaysSinceZero)
+
+	// A party's clock passes its own events and those of the whole
+	// campaign, but only marks its own as occurred
+	passed := commands.PartyEvents(events, "")
+	if m.clockParty != "" {
+		passed = append(passed, commands.PartyEvents(events, m.clockParty)...)
+	}
+	digest := commands.ClockDigest(m.config, cal, previous.DaysSinceZero, date.DaysSinceZero, passed)
+	marked, err := commands.MarkOccurred(digest, m.clockParty)
+	action := fmt.Sprintf("Advance %s from %s to %s", name, previous.DateString(), date.DateString())
+	if recordErr := op.Record(m.config, action, reason); recordErr != nil && err == nil {
+		err = recordErr
+	}
+	if err != nil {
+		m = m.toMenu()
+		m.statusMsg = ui.RenderError(err.Error())
+		return m
+	}
+	if marked > 0 {
+		summary += fmt.Sprintf(", %d scheduled events occurred", marked)
+	}
+
+	// Deadlines coming up or passed go first
+	countdowns, err := config.LoadCountdowns()
+	if err != nil {
+		m = m.toMenu()
+		m.statusMsg = ui.RenderError(err.Error())
+		return m
+	}
+	deadlines := commands.CountdownDigest(m.config, cal, previous.DaysSinceZero, date.DaysSinceZero, countdowns, events)
+	if len(deadlines) > 0 {
+		summary += fmt.Sprintf(", %d deadline warnings", len(deadlines))
+	}
+	digest = append(deadlines, digest...)
+
+	// Player view leaves out events players can't see
+	visible := commands.EventFilter{PlayerView: m.eventFilter.PlayerView}
+	m.clockDigest = nil
+	var dates, kinds, messages []string
+	for _, entry := range digest {
+		if entry.Event != nil && !visible.Match(*entry.Event) {
+			continue
+		}
+		m.clockDigest = append(m.clockDigest, entry)
+		dates = append(dates, entry.Date)
+		kinds = append(kinds, entry.Kind)
+		messages = append(messages, entry.Message)
+	}
+
+	if len(m.clockDigest) == 0 {
+		m = m.toMenu()
+		m.statusMsg = ui.RenderSuccess(summary)
+		return m
+	}
+
+	m.state = stateClockDigest
+	m.menuList.SetItems(ui.DigestListItems(dates, kinds, messages))
+	m.menuList.Title = fmt.Sprintf("Passed On The Way (%d)", len(m.clockDigest))
+	m.statusMsg = ui.RenderSuccess(summary)
+	return m
+}
+
+// updateClockDigest shows the details of the events in the digest
+func (m AppModel) updateClockDigest(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+	index := m.menuList.Index()
+	if index < 0 || index >= len(m.clockDigest) || m.clockDigest[index].Event == nil {
+		return m, cmd
+	}
+
+	events, err := commands.LoadEvents()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, cmd
+	}
+	events = commands.FilterEvents(events, commands.EventFilter{PlayerView: m.eventFilter.PlayerView})
+
+	event := *m.clockDigest[index].Event
+	conns := commands.BuildLinkIndex(events).Connections(event)
+	m.statusMsg = commands.GetEventDetails(event, conns)
+	return m, cmd
+}
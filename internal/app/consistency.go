@@ -0,0 +1,55 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/sksmith/gmcli/internal/commands"
+	"github.com/sksmith/gmcli/internal/config"
+	"github.com/sksmith/gmcli/internal/ui"
+)
+
+// startConsistencyCheck analyses the timeline and lists the issues found.
+// Selecting an issue shows the event's details.
+func (m AppModel) startConsistencyCheck() AppModel {
+	events, err := commands.LoadEvents()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m
+	}
+	characters, err := config.LoadCharacters()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m
+	}
+	locations, err := config.LoadLocations()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m
+	}
+
+	// Player view leaves out issues about events players can't see
+	visible := commands.EventFilter{PlayerView: m.eventFilter.PlayerView}
+
+	var issueEvents []config.Event
+	var kinds, messages []string
+	for _, issue := range commands.CheckConsistency(m.config, events, characters, locations) {
+		if !visible.Match(issue.Event) {
+			continue
+		}
+		issueEvents = append(issueEvents, issue.Event)
+		kinds = append(kinds, issue.Kind)
+		messages = append(messages, issue.Message)
+	}
+
+	if len(issueEvents) == 0 {
+		m.statusMsg = ui.RenderSuccess(fmt.Sprintf("No consistency issues found in %d events.", len(events)))
+		return m
+	}
+
+	m.lookupEvents = issueEvents
+	m.state = stateLookupResults
+	m.menuList.SetItems(ui.IssueListItems(issueEvents, kinds, messages))
+	m.menuList.Title = fmt.Sprintf("Consistency Issues (%d)", len(issueEvents))
+	m.statusMsg = ""
+	return m
+}
@@ -0,0 +1,198 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sksmith/gmcli/internal/commands"
+	"github.com/sksmith/gmcli/internal/config"
+	"github.com/sksmith/gmcli/internal/ui"
+)
+
+// startAddCountdown asks which calendar the countdown runs on
+func (m AppModel) startAddCountdown() AppModel {
+	if len(m.config.Calendars) == 0 {
+		m.statusMsg = ui.RenderError("No calendars available. Create a calendar first.")
+		return m
+	}
+
+	m.state = stateCountdownCalendar
+	m.menuList.SetItems(ui.CalendarListItems(m.config.Calendars))
+	m.menuList.Title = "Select Calendar"
+	m.statusMsg = ""
+	return m
+}
+
+// updateCountdownCalendar starts the countdown form for the selected
+// calendar
+func (m AppModel) updateCountdownCalendar(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+	item, ok := m.menuList.SelectedItem().(ui.Item)
+	if !ok {
+		return m, cmd
+	}
+
+	m.countdownInput = config.Countdown{Calendar: item.Title}
+	m.countdownTarget = ""
+	m.countdownInputStage = 1
+	m.state = stateAddCountdown
+	m.input = ui.NewTextInput("Enter countdown name (e.g., Eclipse ritual)")
+	m.statusMsg = ""
+	return m, cmd
+}
+
+// updateAddCountdown handles the multi-step countdown form
+func (m AppModel) updateAddCountdown(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	input := strings.TrimSpace(m.input.Value())
+
+	switch m.countdownInputStage {
+	case 1: // Name
+		if input == "" {
+			m.statusMsg = ui.RenderError("countdown name cannot be empty")
+			return m, nil
+		}
+		m.countdownInput.Name = input
+		m.input = ui.NewTextInput("Target date (AAYYYY-MM-DD, +23 days...) or event name or ID")
+		m.countdownInputStage = 2
+
+	case 2: // Target
+		if input == "" {
+			m.statusMsg = ui.RenderError("target cannot be empty")
+			return m, nil
+		}
+		m.countdownTarget = input
+		m.input = ui.NewTextInput(fmt.Sprintf("Warn how many days ahead? (blank for %d)", commands.DefaultWarnDays))
+		m.countdownInputStage = 3
+
+	case 3: // Warning days
+		if input != "" {
+			days, err := strconv.Atoi(input)
+			if err != nil {
+				m.statusMsg = ui.RenderError("warning days must be a number")
+				return m, nil
+			}
+			m.countdownInput.WarnDays = days
+		}
+
+		events, err := commands.LoadEvents()
+		if err != nil {
+			m.statusMsg = ui.RenderError(err.Error())
+			return m, nil
+		}
+		run := func() error {
+			return commands.CreateCountdown(m.config, m.countdownInput, m.countdownTarget, events)
+		}
+		return m.track("Add countdown "+m.countdownInput.Name, run, func(m AppModel, err error) AppModel {
+			if err != nil {
+				m.statusMsg = ui.RenderError(fmt.Sprintf("Failed to add countdown: %v", err))
+				return m
+			}
+
+			m.countdownInputStage = 0
+			m = m.toMenu()
+			m.statusMsg = ui.RenderSuccess(fmt.Sprintf("Countdown '%s' added!", m.countdownInput.Name))
+			return m
+		}), nil
+	}
+
+	m.statusMsg = ""
+	return m, cmd
+}
+
+// startCountdowns shows how many days each countdown has left
+func (m AppModel) startCountdowns() AppModel {
+	countdowns, err := config.LoadCountdowns()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m
+	}
+	if len(countdowns) == 0 {
+		m.statusMsg = ui.RenderError("No countdowns yet. Add a countdown first.")
+		return m
+	}
+	events, err := commands.LoadEvents()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m
+	}
+
+	// Player view leaves out countdowns to events players can't see
+	visible := commands.EventFilter{PlayerView: m.eventFilter.PlayerView}
+	m.countdowns = nil
+	for _, status := range commands.CountdownStatuses(m.config, countdowns, events) {
+		if status.Countdown.Event != "" && !visible.Match(status.Target) {
+			continue
+		}
+		m.countdowns = append(m.countdowns, status)
+	}
+	if len(m.countdowns) == 0 {
+		m.statusMsg = ui.RenderMuted("No countdowns to show in player view.")
+		return m
+	}
+
+	list := make([]config.Countdown, len(m.countdowns))
+	states := make([]string, len(m.countdowns))
+	remaining := make([]string, len(m.countdowns))
+	targets := make([]string, len(m.countdowns))
+	for i, status := range m.countdowns {
+		list[i] = status.Countdown
+		states[i] = status.State
+		remaining[i] = status.String()
+		if status.State != commands.CountdownUnknown {
+			targets[i] = status.Target.DateString()
+		}
+	}
+
+	m.state = stateCountdowns
+	m.menuList.SetItems(ui.CountdownListItems(list, states, remaining, targets))
+	m.menuList.Title = "Countdowns"
+	m.statusMsg = ""
+	return m
+}
+
+// updateCountdowns shows the event a selected countdown is tied to
+func (m AppModel) updateCountdowns(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+	index := m.menuList.Index()
+	if index < 0 || index >= len(m.countdowns) {
+		return m, cmd
+	}
+
+	// Countdowns on a date, or on an event players can't see, have no
+	// details beyond the days left
+	status := m.countdowns[index]
+	visible := commands.EventFilter{PlayerView: m.eventFilter.PlayerView}
+	if status.Countdown.Event == "" || status.State == commands.CountdownUnknown || !visible.Match(status.Target) {
+		m.statusMsg = ui.RenderMuted(fmt.Sprintf("%s: %s", status.Countdown.Name, status))
+		return m, cmd
+	}
+
+	events, err := commands.LoadEvents()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, cmd
+	}
+	events = commands.FilterEvents(events, commands.EventFilter{PlayerView: m.eventFilter.PlayerView})
+	m.statusMsg = commands.GetEventDetails(status.Target, commands.BuildLinkIndex(events).Connections(status.Target))
+	return m, cmd
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sksmith/gmcli/internal/commands"
+	"github.com/sksmith/gmcli/internal/ui"
+)
+
+// eventDatePrompt hints at the accepted event date forms
+const eventDatePrompt = "AAYYYY-MM-DD, today, +3d, next Forge Fire 12, 2 days after <event>..."
+
+// updateEventDate resolves the entered event date, asking for confirmation
+// when it was given relative to the campaign or in words
+func (m AppModel) updateEventDate(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	m.eventDateStr = strings.TrimSpace(m.input.Value())
+	cal := m.config.Calendars[m.eventCalendarIndex]
+
+	events, err := commands.LoadEvents()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+	today, hasToday := commands.CampaignToday(cal, m.config.DaysInYear, events)
+	party, playing := m.activePartyIn(cal.Name)
+	if playing {
+		if date, err := commands.PartyDate(m.config, party); err == nil {
+			today, hasToday = date.DaysSinceZero, true
+		}
+	}
+
+	eventData, err := commands.ResolveDate(m.eventDateStr, cal, m.config.DaysInYear, today, hasToday, events)
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+	m.eventData = eventData
+	if playing {
+		m.eventData.Party = party.Name
+	}
+
+	// Dates typed out in full need no confirmation
+	if strings.EqualFold(eventData.DateString(), m.eventDateStr) {
+		return m.continueAfterEventDate(), nil
+	}
+
+	m.state = stateEventDateConfirm
+	m.input = ui.NewTextInput("Use this date? (Y/n)")
+	m.statusMsg = ui.RenderHighlight(fmt.Sprintf("%s resolves to %s", m.eventDateStr, commands.DescribeDate(cal, eventData)))
+	if weekday := commands.Weekday(cal, eventData.DaysSinceZero); weekday != "" && !eventData.IsApproximate() {
+		m.statusMsg += ui.RenderMuted(fmt.Sprintf(" (%s)", weekday))
+	}
+	return m, nil
+}
+
+// updateEventDateConfirm accepts the resolved date or goes back to the date
+// input
+func (m AppModel) updateEventDateConfirm(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(m.input.Value()))
+	if answer == "n" || answer == "no" {
+		m.state = stateEventDate
+		m.input = ui.NewTextInput(eventDatePrompt)
+		m.input.SetValue(m.eventDateStr)
+		m.statusMsg = ""
+		return m, nil
+	}
+
+	return m.continueAfterEventDate(), nil
+}
+
+// continueAfterEventDate moves on to the end date, or straight to the name
+// for approximate dates since they can't span or repeat
+func (m AppModel) continueAfterEventDate() AppModel {
+	if m.eventData.IsApproximate() {
+		m.state = stateEventName
+		m.input = ui.NewTextInput("Enter event name")
+		m.statusMsg = fmt.Sprintf("Event date: %s", m.eventData.DateDescription())
+		return m
+	}
+
+	m.state = stateEventEnd
+	m.input = ui.NewTextInput("End date (AAYYYY-MM-DD), duration in days, or blank for one day")
+	m.statusMsg = fmt.Sprintf("Event date: %s (Days since 0: %d)",
+		m.eventData.DateString(), m.eventData.DaysSinceZero)
+	return m
+}
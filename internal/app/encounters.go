@@ -0,0 +1,213 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sksmith/gmcli/internal/commands"
+	"github.com/sksmith/gmcli/internal/config"
+	"github.com/sksmith/gmcli/internal/ui"
+)
+
+// startEncounter lists the workspace's encounter tables to roll on
+func (m AppModel) startEncounter() AppModel {
+	tables, err := commands.LoadEncounterTables()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m
+	}
+	if len(tables) == 0 {
+		m.statusMsg = ui.RenderError(fmt.Sprintf("No encounter tables found. Add one to %s/.", config.EncountersDir))
+		return m
+	}
+
+	m.encounterTables = tables
+	m.state = stateEncounterTable
+	m.menuList.SetItems(ui.EncounterTableListItems(tables))
+	m.menuList.Title = "Select Encounter Table"
+	m.statusMsg = ""
+	return m
+}
+
+// updateEncounterTable picks the calendar to roll in for the selected
+// table: the active party's, the table's own, or the only one there is
+func (m AppModel) updateEncounterTable(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+	index := m.menuList.Index()
+	if index < 0 || index >= len(m.encounterTables) {
+		return m, cmd
+	}
+	m.encounterTable = m.encounterTables[index]
+
+	calendar := m.encounterTable.Calendar
+	if index := commands.FindParty(m.parties, m.activeParty); index >= 0 {
+		calendar = m.parties[index].Calendar
+	}
+	if calendar == "" && len(m.config.Calendars) == 1 {
+		calendar = m.config.Calendars[0].Name
+	}
+	if calendar != "" {
+		for i, cal := range m.config.Calendars {
+			if cal.Name == calendar {
+				return m.startEncounterIn(i), cmd
+			}
+		}
+		m.statusMsg = ui.RenderError(fmt.Sprintf("calendar '%s' not found", calendar))
+		return m, cmd
+	}
+
+	m.state = stateEncounterCalendar
+	m.menuList.SetItems(ui.CalendarListItems(m.config.Calendars))
+	m.menuList.Title = "Select Calendar"
+	m.statusMsg = ""
+	return m, cmd
+}
+
+// updateEncounterCalendar rolls in the selected calendar
+func (m AppModel) updateEncounterCalendar(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+	item, ok := m.menuList.SelectedItem().(ui.Item)
+	if !ok {
+		return m, cmd
+	}
+
+	for i, cal := range m.config.Calendars {
+		if cal.Name == item.Title {
+			return m.startEncounterIn(i), cmd
+		}
+	}
+	return m, cmd
+}
+
+// startEncounterIn rolls for the current date of the active party, or of
+// the calendar's clock, asking for the time of day first
+func (m AppModel) startEncounterIn(calIndex int) AppModel {
+	cal := m.config.Calendars[calIndex]
+
+	current := cal.CurrentDate
+	if party, ok := m.activePartyIn(cal.Name); ok {
+		current = party.CurrentDate
+	}
+	if current == "" {
+		m = m.toMenu()
+		m.statusMsg = ui.RenderError(fmt.Sprintf("%s has no current date. Set it with the Campaign Clock first.", cal.Name))
+		return m
+	}
+	date, err := commands.ValidateEventDate(current, cal, m.config.DaysInYear)
+	if err != nil {
+		m = m.toMenu()
+		m.statusMsg = ui.RenderError(err.Error())
+		return m
+	}
+
+	m.encounterCalendarIndex = calIndex
+	m.encounterDate = date
+	m.encounterInputStage = 1
+	m.state = stateEncounter
+	m.input = ui.NewTextInput(fmt.Sprintf("Time of day (%s), blank for any", strings.Join(commands.TimesOfDay, ", ")))
+	m.statusMsg = ui.RenderMuted(fmt.Sprintf("Rolling on %s for %s.", m.encounterTable.Name, commands.DescribeDay(cal, date)))
+	return m
+}
+
+// updateEncounter handles the multi-step roll, then offers to log the
+// result as an event
+func (m AppModel) updateEncounter(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	input := strings.TrimSpace(m.input.Value())
+	cal := m.config.Calendars[m.encounterCalendarIndex]
+
+	switch m.encounterInputStage {
+	case 1: // Time of day
+		m.encounterTime = input
+		m.input = ui.NewTextInput("Location or region, blank for none")
+		m.input.SetValue(m.encounterTable.Location)
+		m.encounterInputStage = 2
+
+	case 2: // Location
+		m.encounterLocation = input
+		m.input = ui.NewTextInput("Seed, blank for a random one")
+		m.encounterInputStage = 3
+
+	case 3: // Seed
+		seed := commands.NewSeed()
+		if input != "" {
+			parsed, err := strconv.ParseInt(input, 10, 64)
+			if err != nil {
+				m.statusMsg = ui.RenderError("seed must be a whole number")
+				return m, nil
+			}
+			seed = parsed
+		}
+
+		locations, err := config.LoadLocations()
+		if err != nil {
+			m.statusMsg = ui.RenderError(err.Error())
+			return m, nil
+		}
+		cond := commands.NewEncounterConditions(cal, m.encounterDate, m.encounterTime, m.encounterLocation)
+		roll, err := commands.RollEncounter(m.encounterTable, cond, locations, seed)
+		if err != nil {
+			m.statusMsg = ui.RenderError(err.Error())
+			return m, nil
+		}
+		m.encounterRoll = roll
+		m.input = ui.NewTextInput("Log it as an event? (y/N)")
+		m.encounterInputStage = 4
+		m.statusMsg = ui.RenderHighlight(fmt.Sprintf("%s (%.0f%% chance, seed %d)", roll.Entry.Result, roll.Chance*100, roll.Seed)) +
+			"\n" + ui.RenderMuted(cond.String())
+		return m, nil
+
+	case 4: // Log the result
+		m.encounterInputStage = 0
+		answer := strings.ToLower(input)
+		if answer != "y" && answer != "yes" {
+			m = m.toMenu()
+			m.statusMsg = ui.RenderSuccess(fmt.Sprintf("Rolled %s.", m.encounterRoll.Entry.Result))
+			return m, nil
+		}
+
+		locations, err := config.LoadLocations()
+		if err != nil {
+			m.statusMsg = ui.RenderError(err.Error())
+			return m, nil
+		}
+		partyName := ""
+		if party, ok := m.activePartyIn(cal.Name); ok {
+			partyName = party.Name
+		}
+		run := func() error {
+			return commands.LogEncounter(m.config, cal, m.encounterRoll, locations, partyName)
+		}
+		return m.track("Log encounter "+m.encounterRoll.Entry.Result, run, func(m AppModel, err error) AppModel {
+			if err != nil {
+				m.statusMsg = ui.RenderError(err.Error())
+				return m
+			}
+			m = m.toMenu()
+			m.statusMsg = ui.RenderSuccess(fmt.Sprintf("Logged %s on %s.", m.encounterRoll.Entry.Result, m.encounterDate.DateString()))
+			return m
+		}), nil
+	}
+
+	m.statusMsg = ""
+	return m, cmd
+}
@@ -0,0 +1,177 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sksmith/gmcli/internal/commands"
+	"github.com/sksmith/gmcli/internal/config"
+	"github.com/sksmith/gmcli/internal/ui"
+)
+
+// trackedAction is a state-changing operation waiting for the reason
+// behind it before it runs
+type trackedAction struct {
+	action string
+	run    func() error
+	done   func(AppModel, error) AppModel
+
+	// The screen to go back to once the reason is given
+	state     string
+	input     textinput.Model
+	statusMsg string
+}
+
+// track asks for the reason behind a state-changing operation, then runs
+// it, recording what it changed in the history so it can be undone. done
+// carries on from the screen track was called on, with the error the
+// operation returned.
+func (m AppModel) track(action string, run func() error, done func(AppModel, error) AppModel) AppModel {
+	m.tracked = &trackedAction{action: action, run: run, done: done,
+		state: m.state, input: m.input, statusMsg: m.statusMsg}
+	m.state = stateReason
+	m.input = ui.NewTextInput("Reason, or blank")
+	m.statusMsg = ""
+	return m
+}
+
+// retrack runs a follow-up to the last tracked operation straight away,
+// recording it with the same reason
+func (m AppModel) retrack(action string, run func() error, done func(AppModel, error) AppModel) AppModel {
+	m.tracked = &trackedAction{action: action, run: run, done: done,
+		state: m.state, input: m.input, statusMsg: m.statusMsg}
+	return m.runTracked()
+}
+
+// updateReason runs the waiting operation once its reason is entered
+func (m AppModel) updateReason(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+	m.reason = strings.TrimSpace(m.input.Value())
+	return m.runTracked(), nil
+}
+
+// runTracked runs the waiting operation with the last reason given
+func (m AppModel) runTracked() AppModel {
+	tracked := m.tracked
+	m.tracked = nil
+	m.state, m.input, m.statusMsg = tracked.state, tracked.input, tracked.statusMsg
+
+	err := commands.Track(m.config, tracked.action, m.reason, tracked.run)
+	return tracked.done(m, err)
+}
+
+// startHistory lists the recorded operations, most recent first
+func (m AppModel) startHistory() AppModel {
+	history, err := config.LoadHistory()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m
+	}
+	if len(history) == 0 {
+		m = m.toMenu()
+		m.statusMsg = ui.RenderMuted("Nothing has been recorded yet.")
+		return m
+	}
+
+	m.history = make([]config.HistoryEntry, len(history))
+	for i, entry := range history {
+		m.history[len(history)-1-i] = entry
+	}
+	m.state = stateHistory
+	m.menuList.SetItems(ui.HistoryListItems(m.history))
+	m.menuList.Title = "History (enter to undo or redo up to an entry)"
+	m.statusMsg = ""
+	return m
+}
+
+// updateHistory asks to undo every operation down to the selected one, or
+// to redo every undone operation up to it
+func (m AppModel) updateHistory(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+	index := m.menuList.Index()
+	if index < 0 || index >= len(m.history) {
+		return m, cmd
+	}
+	selected := m.history[index]
+
+	// The history is listed most recent first, so the operations to undo
+	// are those above the selected one and the ones to redo those below
+	m.historySteps = 0
+	m.historyRedo = selected.Undone
+	for i, entry := range m.history {
+		if entry.Undone != m.historyRedo {
+			continue
+		}
+		if (!m.historyRedo && i <= index) || (m.historyRedo && i >= index) {
+			m.historySteps++
+		}
+	}
+
+	verb := "Undo"
+	if m.historyRedo {
+		verb = "Redo"
+	}
+	m.state = stateHistoryConfirm
+	m.input = ui.NewTextInput(fmt.Sprintf("%s %d operations, through '%s'? (y/N)", verb, m.historySteps, selected.Action))
+	m.statusMsg = ""
+	return m, cmd
+}
+
+// updateHistoryConfirm undoes or redoes the operations once confirmed and
+// reloads the state they changed
+func (m AppModel) updateHistoryConfirm(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(m.input.Value()))
+	if answer != "y" && answer != "yes" {
+		m = m.startHistory()
+		m.statusMsg = ui.RenderMuted("Nothing was changed.")
+		return m, nil
+	}
+
+	var entries []config.HistoryEntry
+	var err error
+	verb := "Undid"
+	if m.historyRedo {
+		verb = "Redid"
+		entries, err = commands.Redo(m.historySteps)
+	} else {
+		entries, err = commands.Undo(m.historySteps)
+	}
+
+	// Whatever was undone before an error still needs reloading
+	if cfg, loadErr := config.Load(); loadErr == nil {
+		m.config = cfg
+	}
+	m = m.reloadParties()
+
+	m = m.startHistory()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+	actions := make([]string, len(entries))
+	for i, entry := range entries {
+		actions[i] = entry.Action
+	}
+	m.statusMsg = ui.RenderSuccess(fmt.Sprintf("%s %d operations: %s.", verb, len(entries), strings.Join(actions, "; ")))
+	return m, nil
+}
@@ -0,0 +1,131 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sksmith/gmcli/internal/commands"
+	"github.com/sksmith/gmcli/internal/ui"
+)
+
+// maxImportErrorsShown caps the import errors listed in the status line,
+// the full list is written to a report file
+const maxImportErrorsShown = 10
+
+// startImport asks for the file to import events from
+func (m AppModel) startImport() AppModel {
+	m.state = stateImportPath
+	m.input = ui.NewTextInput("Path to a .csv or .json file")
+	m.statusMsg = ui.RenderMuted("Columns: calendar, date, name, tags, body")
+	return m
+}
+
+// updateImportPath reads the import file and dry-runs it
+func (m AppModel) updateImportPath(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	rows, err := commands.ReadImportFile(strings.TrimSpace(m.input.Value()))
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+
+	report, err := commands.ImportEvents(m.config, rows, true)
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+
+	m.importRows = rows
+	m.statusMsg = m.importSummary(report)
+	if len(report.Events) == 0 {
+		m = m.toMenu()
+		return m, nil
+	}
+
+	m.state = stateImportConfirm
+	m.input = ui.NewTextInput(fmt.Sprintf("Create %d events? (y/N)", len(report.Events)))
+	return m, nil
+}
+
+// updateImportConfirm creates the valid events once confirmed
+func (m AppModel) updateImportConfirm(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(m.input.Value()))
+	if answer != "y" && answer != "yes" {
+		m.statusMsg = ui.RenderMuted("Import cancelled, nothing was created.")
+		m = m.toMenu()
+		return m, nil
+	}
+
+	var report commands.ImportReport
+	run := func() error {
+		var err error
+		report, err = commands.ImportEvents(m.config, m.importRows, false)
+		return err
+	}
+	return m.track(fmt.Sprintf("Import %d events", len(m.importRows)), run, func(m AppModel, err error) AppModel {
+		if err != nil {
+			m.statusMsg = ui.RenderError(err.Error())
+		} else {
+			m.statusMsg = m.importSummary(report)
+		}
+
+		m.importRows = nil
+		m = m.toMenu()
+		return m
+	}), nil
+}
+
+// importSummary describes an import report, listing the bad rows
+func (m AppModel) importSummary(report commands.ImportReport) string {
+	verb := "Created"
+	if report.DryRun {
+		verb = "Ready to create"
+	}
+
+	var b strings.Builder
+	b.WriteString(ui.RenderSuccess(fmt.Sprintf("%s %d events.", verb, len(report.Events))))
+	if len(report.Errors) == 0 {
+		return b.String()
+	}
+
+	b.WriteString(ui.RenderError(fmt.Sprintf(" %d rows have errors:", len(report.Errors))))
+	for i, e := range report.Errors {
+		if i == maxImportErrorsShown {
+			b.WriteString("\n" + ui.RenderMuted(fmt.Sprintf("...and %d more", len(report.Errors)-i)))
+			break
+		}
+		b.WriteString("\n" + ui.RenderError(e.Error()))
+	}
+
+	if len(report.Errors) > maxImportErrorsShown {
+		path := filepath.Join(commands.ExportsDir, "import-errors.txt")
+		var lines []string
+		for _, e := range report.Errors {
+			lines = append(lines, e.Error())
+		}
+		if err := os.MkdirAll(commands.ExportsDir, 0755); err == nil {
+			if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err == nil {
+				b.WriteString(ui.RenderMuted(fmt.Sprintf(" (full list in %s)", path)))
+			}
+		}
+	}
+
+	return b.String()
+}
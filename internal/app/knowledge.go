@@ -0,0 +1,181 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sksmith/gmcli/internal/commands"
+	"github.com/sksmith/gmcli/internal/config"
+	"github.com/sksmith/gmcli/internal/ui"
+)
+
+// startRecordKnowledge begins recording that a character learned of an event
+func (m AppModel) startRecordKnowledge() AppModel {
+	m.state = stateKnowledgeEvent
+	m.input = ui.NewTextInput("Event name or ID")
+	m.statusMsg = ""
+	return m
+}
+
+// updateKnowledgeEvent handles choosing the event a character learned of
+func (m AppModel) updateKnowledgeEvent(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	events, err := commands.LoadEvents()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+
+	event, err := commands.FindEvent(events, strings.TrimSpace(m.input.Value()))
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+
+	m.knowledgeEvent = event
+	m.state = stateKnowledgeCharacter
+	m.input = ui.NewTextInput("Character name")
+	m.statusMsg = fmt.Sprintf("Event: %s (%s)", event.Name, event.DateString())
+	return m, cmd
+}
+
+// updateKnowledgeCharacter handles choosing the character who learned of
+// the event
+func (m AppModel) updateKnowledgeCharacter(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	characters, err := config.LoadCharacters()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+
+	ch, ok := commands.FindCharacter(characters, strings.TrimSpace(m.input.Value()))
+	if !ok {
+		m.statusMsg = ui.RenderError(fmt.Sprintf("character '%s' not found", strings.TrimSpace(m.input.Value())))
+		return m, nil
+	}
+
+	m.knowledgeCharacter = ch.Name
+	m.state = stateKnowledgeDate
+	m.input = ui.NewTextInput("Date learned (AAYYYY-MM-DD)")
+	m.input.SetValue(m.knowledgeEvent.DateString())
+	return m, cmd
+}
+
+// updateKnowledgeDate records the knowledge once the date is entered
+func (m AppModel) updateKnowledgeDate(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	action := fmt.Sprintf("Record that %s knows about %s", m.knowledgeCharacter, m.knowledgeEvent.Name)
+	learned := strings.TrimSpace(m.input.Value())
+	run := func() error {
+		return commands.RecordKnowledge(m.config, m.knowledgeEvent, m.knowledgeCharacter, learned)
+	}
+	return m.track(action, run, func(m AppModel, err error) AppModel {
+		if err != nil {
+			m.statusMsg = ui.RenderError(err.Error())
+			return m
+		}
+
+		m.statusMsg = ui.RenderSuccess(fmt.Sprintf("%s now knows about '%s'.", m.knowledgeCharacter, m.knowledgeEvent.Name))
+		m = m.toMenu()
+		return m
+	}), nil
+}
+
+// startCharacterKnowledge lists characters to query what they know
+func (m AppModel) startCharacterKnowledge() AppModel {
+	m = m.startCharacters()
+	if m.state == stateCharacters {
+		m.state = stateKnowledgeCharacters
+		m.menuList.Title = "Character Knowledge"
+		m.statusMsg = ui.RenderMuted("Select a character to list and export what they know.")
+	}
+	return m
+}
+
+// updateKnowledgeCharacters handles selecting a character to query
+func (m AppModel) updateKnowledgeCharacters(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if key.Matches(msg, m.keymap.Enter) {
+		index := m.menuList.Index()
+		if index >= 0 && index < len(m.characters) {
+			m.characterIndex = index
+			m.state = stateKnowledgeAsOf
+			m.input = ui.NewTextInput("As of date (AAYYYY-MM-DD)")
+			m.statusMsg = ""
+		}
+	}
+
+	return m, cmd
+}
+
+// updateKnowledgeAsOf lists and exports what the selected character knows
+// as of a date
+func (m AppModel) updateKnowledgeAsOf(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	ch := m.characters[m.characterIndex]
+	cal, err := commands.FindCalendar(m.config, ch.Calendar)
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+
+	asOf, err := commands.ValidateEventDate(strings.TrimSpace(m.input.Value()), cal, m.config.DaysInYear)
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+
+	events, err := commands.LoadEvents()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+	events = commands.FilterEvents(events, m.eventFilter)
+
+	path, err := commands.ExportCharacterKnowledge(events, ch, asOf)
+	if err != nil {
+		m.statusMsg = ui.RenderError(fmt.Sprintf("Failed to export knowledge: %v", err))
+		return m, nil
+	}
+
+	m.lookupEvents = commands.KnownEvents(events, ch.Name, asOf.DaysSinceZero)
+	if len(m.lookupEvents) == 0 {
+		m.statusMsg = ui.RenderMuted(fmt.Sprintf("%s knows of no events as of %s.", ch.Name, asOf.DateString()))
+		return m, cmd
+	}
+
+	m.state = stateLookupResults
+	m.menuList.SetItems(ui.EventListItems(m.lookupEvents))
+	m.menuList.Title = fmt.Sprintf("What %s Knows as of %s", ch.Name, asOf.DateString())
+	m.statusMsg = ui.RenderSuccess(fmt.Sprintf("Exported to %s", path))
+	return m, cmd
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sksmith/gmcli/internal/commands"
+	"github.com/sksmith/gmcli/internal/config"
+	"github.com/sksmith/gmcli/internal/ui"
+)
+
+// startAddLocation begins the location creation flow
+func (m AppModel) startAddLocation() AppModel {
+	m.locationInput = config.Location{}
+	m.locationInputStage = 1
+	m.state = stateAddLocation
+	m.input = ui.NewTextInput("Enter location name")
+	m.statusMsg = ""
+	return m
+}
+
+// updateAddLocation handles the multi-step location creation flow
+func (m AppModel) updateAddLocation(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	input := strings.TrimSpace(m.input.Value())
+
+	switch m.locationInputStage {
+	case 1: // Name
+		if input == "" {
+			m.statusMsg = ui.RenderError("location name cannot be empty")
+			return m, nil
+		}
+		m.locationInput.Name = input
+		m.input = ui.NewTextInput(fmt.Sprintf("Kind (%s), or blank", strings.Join(commands.LocationKinds, ", ")))
+		m.locationInputStage = 2
+
+	case 2: // Kind
+		m.locationInput.Kind = input
+		m.input = ui.NewTextInput("Enclosing location name, or blank for a top-level place")
+		m.locationInputStage = 3
+
+	case 3: // Parent
+		m.locationInput.Parent = input
+
+		run := func() error {
+			return commands.CreateLocation(m.locationInput)
+		}
+		return m.track("Add location "+m.locationInput.Name, run, func(m AppModel, err error) AppModel {
+			if err != nil {
+				m.statusMsg = ui.RenderError(fmt.Sprintf("Failed to add location: %v", err))
+				return m
+			}
+
+			m.statusMsg = ui.RenderSuccess(fmt.Sprintf("Location '%s' added!", m.locationInput.Name))
+			m.locationInputStage = 0
+			m = m.toMenu()
+			return m
+		}), nil
+	}
+
+	m.statusMsg = ""
+	return m, cmd
+}
+
+// startLocations shows the location hierarchy
+func (m AppModel) startLocations() AppModel {
+	locations, err := config.LoadLocations()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m
+	}
+	if len(locations) == 0 {
+		m.statusMsg = ui.RenderError("No locations yet. Add a location first.")
+		return m
+	}
+
+	ordered, depths := commands.LocationTree(locations)
+	m.locations = ordered
+	m.state = stateLocations
+	m.menuList.SetItems(ui.LocationListItems(ordered, depths))
+	m.menuList.Title = "Locations"
+	m.statusMsg = ui.RenderMuted("Select a place to list its events.")
+	return m
+}
+
+// updateLocations handles selecting a place to list its events
+func (m AppModel) updateLocations(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	index := m.menuList.Index()
+	if index < 0 || index >= len(m.locations) {
+		return m, cmd
+	}
+	place := m.locations[index].Name
+
+	events, err := commands.LoadEvents()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+
+	events = commands.FilterEvents(events, m.eventFilter)
+	m.lookupEvents = commands.EventsAtLocation(events, m.locations, place)
+	if len(m.lookupEvents) == 0 {
+		m.statusMsg = ui.RenderMuted(fmt.Sprintf("No events at %s.", place))
+		return m, cmd
+	}
+
+	m.state = stateLookupResults
+	m.menuList.SetItems(ui.EventListItems(m.lookupEvents))
+	m.menuList.Title = fmt.Sprintf("Events At %s", place)
+	m.statusMsg = ""
+	return m, cmd
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sksmith/gmcli/internal/commands"
+	"github.com/sksmith/gmcli/internal/ui"
+)
+
+// startMigrate previews how many event files would move into the
+// configured layout and asks for confirmation
+func (m AppModel) startMigrate() AppModel {
+	events, err := commands.LoadEvents()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m
+	}
+
+	moves, err := commands.PlanMigration(m.config, events)
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m
+	}
+	legacy, err := commands.FindLegacyEventFiles()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m
+	}
+	if len(moves) == 0 && len(legacy) == 0 {
+		m.statusMsg = ui.RenderMuted("Every event file already matches the layout.")
+		return m
+	}
+
+	m.state = stateMigrateConfirm
+	if len(legacy) > 0 {
+		m.input = ui.NewTextInput(fmt.Sprintf("Move %d files and upgrade %d old files without front matter? (y/N)", len(moves), len(legacy)))
+	} else {
+		m.input = ui.NewTextInput(fmt.Sprintf("Move %d files? (y/N)", len(moves)))
+	}
+	m.statusMsg = ""
+	if len(moves) > 0 {
+		m.statusMsg = ui.RenderMuted(fmt.Sprintf("e.g. %s -> %s", moves[0].From, moves[0].To))
+	}
+	return m
+}
+
+// updateMigrateConfirm moves the event files once confirmed
+func (m AppModel) updateMigrateConfirm(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(m.input.Value()))
+	if answer != "y" && answer != "yes" {
+		m.statusMsg = ui.RenderMuted("Migration cancelled.")
+		m = m.toMenu()
+		return m, nil
+	}
+
+	var report commands.MigrationReport
+	run := func() error {
+		var err error
+		report, err = commands.MigrateEventFiles(m.config)
+		return err
+	}
+	return m.track("Migrate event files", run, func(m AppModel, err error) AppModel {
+		if err != nil {
+			m.statusMsg = ui.RenderError(fmt.Sprintf("Failed to migrate event files: %v", err))
+		} else {
+			m.statusMsg = ui.RenderSuccess(fmt.Sprintf("Moved %d event files, added front matter to %d old ones.", report.Moved, report.Upgraded))
+		}
+		if len(report.Skipped) > 0 {
+			m.statusMsg += "\n" + ui.RenderError("Couldn't read as events:\n"+strings.Join(report.Skipped, "\n"))
+		}
+
+		m = m.toMenu()
+		return m
+	}), nil
+}
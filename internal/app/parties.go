@@ -0,0 +1,330 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sksmith/gmcli/internal/commands"
+	"github.com/sksmith/gmcli/internal/config"
+	"github.com/sksmith/gmcli/internal/ui"
+)
+
+// noPartyTitle is the Select Party entry clearing the active party
+const noPartyTitle = "No party"
+
+// activePartyIn returns the active party when it plays in the calendar
+func (m AppModel) activePartyIn(calendar string) (config.Party, bool) {
+	if m.activeParty == "" {
+		return config.Party{}, false
+	}
+	index := commands.FindParty(m.parties, m.activeParty)
+	if index < 0 || m.parties[index].Calendar != calendar {
+		return config.Party{}, false
+	}
+	return m.parties[index], true
+}
+
+// describePartyDate words a party's current date with its weekday and
+// month name
+func (m AppModel) describePartyDate(party config.Party) string {
+	cal, err := commands.FindCalendar(m.config, party.Calendar)
+	if err != nil {
+		return party.CurrentDate
+	}
+	date, err := commands.PartyDate(m.config, party)
+	if err != nil {
+		return party.CurrentDate
+	}
+	return commands.DescribeDay(cal, date)
+}
+
+// reloadParties refreshes the parties after they changed on disk
+func (m AppModel) reloadParties() AppModel {
+	parties, err := config.LoadParties()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m
+	}
+	m.parties = parties
+	return m
+}
+
+// startAddParty asks which calendar the party lives in
+func (m AppModel) startAddParty() AppModel {
+	if len(m.config.Calendars) == 0 {
+		m.statusMsg = ui.RenderError("No calendars available. Create a calendar first.")
+		return m
+	}
+
+	m.state = statePartyCalendar
+	m.menuList.SetItems(ui.CalendarListItems(m.config.Calendars))
+	m.menuList.Title = "Select Calendar"
+	m.statusMsg = ""
+	return m
+}
+
+// updatePartyCalendar starts the party form for the selected calendar
+func (m AppModel) updatePartyCalendar(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+	item, ok := m.menuList.SelectedItem().(ui.Item)
+	if !ok {
+		return m, cmd
+	}
+
+	m.partyInput = config.Party{Calendar: item.Title}
+	m.partyInputStage = 1
+	m.state = stateAddParty
+	m.input = ui.NewTextInput("Enter party name")
+	m.statusMsg = ""
+	return m, cmd
+}
+
+// updateAddParty handles the multi-step party form
+func (m AppModel) updateAddParty(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	input := strings.TrimSpace(m.input.Value())
+
+	switch m.partyInputStage {
+	case 1: // Name
+		if input == "" {
+			m.statusMsg = ui.RenderError("party name cannot be empty")
+			return m, nil
+		}
+		m.partyInput.Name = input
+		m.input = ui.NewTextInput("Current date (AAYYYY-MM-DD), or blank for the calendar's")
+		if cal, err := commands.FindCalendar(m.config, m.partyInput.Calendar); err == nil {
+			m.input.SetValue(cal.CurrentDate)
+		}
+		m.partyInputStage = 2
+
+	case 2: // Current date
+		m.partyInput.CurrentDate = input
+		run := func() error {
+			return commands.CreateParty(m.config, m.partyInput)
+		}
+		return m.track("Add party "+m.partyInput.Name, run, func(m AppModel, err error) AppModel {
+			if err != nil {
+				m.statusMsg = ui.RenderError(fmt.Sprintf("Failed to add party: %v", err))
+				return m
+			}
+
+			m.partyInputStage = 0
+			m = m.reloadParties()
+			m = m.toMenu()
+			m.statusMsg = ui.RenderSuccess(fmt.Sprintf("Party '%s' added!", m.partyInput.Name))
+			return m
+		}), nil
+	}
+
+	m.statusMsg = ""
+	return m, cmd
+}
+
+// partyItems lists the parties with where each is in time, relative to
+// its calendar's clock when that is set
+func (m AppModel) partyItems() []list.Item {
+	dates := make([]string, len(m.parties))
+	for i, party := range m.parties {
+		dates[i] = m.describePartyDate(party)
+
+		cal, err := commands.FindCalendar(m.config, party.Calendar)
+		if err != nil {
+			continue
+		}
+		current, ok, err := commands.CurrentDate(cal, m.config.DaysInYear)
+		date, dateErr := commands.PartyDate(m.config, party)
+		if err != nil || !ok || dateErr != nil {
+			continue
+		}
+		switch diff := date.DaysSinceZero - current.DaysSinceZero; {
+		case diff > 0:
+			dates[i] += fmt.Sprintf(", %d days ahead of the campaign", diff)
+		case diff < 0:
+			dates[i] += fmt.Sprintf(", %d days behind the campaign", -diff)
+		}
+	}
+	return ui.PartyListItems(m.parties, dates)
+}
+
+// startParties shows where each party is in time
+func (m AppModel) startParties() AppModel {
+	m = m.reloadParties()
+	if len(m.parties) == 0 {
+		m.statusMsg = ui.RenderError("No parties yet. Add a party first.")
+		return m
+	}
+
+	m.state = stateParties
+	m.menuList.SetItems(m.partyItems())
+	m.menuList.Title = "Parties"
+	m.statusMsg = ui.RenderMuted("Select a party to advance its clock.")
+	return m
+}
+
+// updateParties asks how far to move the selected party's clock
+func (m AppModel) updateParties(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+	index := m.menuList.Index()
+	if index < 0 || index >= len(m.parties) {
+		return m, cmd
+	}
+	party := m.parties[index]
+
+	calIndex := -1
+	for i, cal := range m.config.Calendars {
+		if cal.Name == party.Calendar {
+			calIndex = i
+		}
+	}
+	if calIndex < 0 {
+		m.statusMsg = ui.RenderError(fmt.Sprintf("calendar '%s' not found", party.Calendar))
+		return m, cmd
+	}
+
+	m.clockCalendarIndex = calIndex
+	m.clockParty = party.Name
+	m.clockInputStage = 1
+	m.state = stateClockAdvance
+	m.input = ui.NewTextInput(clockPrompt)
+	m.statusMsg = ui.RenderHighlight("Current date: " + m.describePartyDate(party))
+	return m, cmd
+}
+
+// startSelectParty lists the parties new events can be attributed to
+func (m AppModel) startSelectParty() AppModel {
+	m = m.reloadParties()
+	if len(m.parties) == 0 {
+		m.statusMsg = ui.RenderError("No parties yet. Add a party first.")
+		return m
+	}
+
+	items := append(m.partyItems(), ui.Item{Title: noPartyTitle, Description: "Events happen to the whole campaign"})
+	m.state = stateSelectParty
+	m.menuList.SetItems(items)
+	m.menuList.Title = "Select Party"
+	m.statusMsg = ""
+	return m
+}
+
+// updateSelectParty makes the selected party the active one
+func (m AppModel) updateSelectParty(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+	item, ok := m.menuList.SelectedItem().(ui.Item)
+	if !ok {
+		return m, cmd
+	}
+
+	m = m.toMenu()
+	if item.Title == noPartyTitle {
+		m.activeParty = ""
+		m.statusMsg = ui.RenderSuccess("New events happen to the whole campaign.")
+		return m, cmd
+	}
+	m.activeParty = item.Title
+	m.statusMsg = ui.RenderSuccess(fmt.Sprintf("New events happen to %s and start from its date.", item.Title))
+	return m, cmd
+}
+
+// startCatchUp asks for the first of the two parties to compare
+func (m AppModel) startCatchUp() AppModel {
+	m = m.reloadParties()
+	if len(m.parties) < 2 {
+		m.statusMsg = ui.RenderError("Catching up needs at least two parties.")
+		return m
+	}
+
+	m.state = stateCatchUpFirst
+	m.menuList.SetItems(m.partyItems())
+	m.menuList.Title = "Catch Up - First Party"
+	m.statusMsg = ""
+	return m
+}
+
+// updateCatchUpFirst asks for the second party
+func (m AppModel) updateCatchUpFirst(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+	index := m.menuList.Index()
+	if index < 0 || index >= len(m.parties) {
+		return m, cmd
+	}
+
+	m.catchUpParty = m.parties[index].Name
+	m.state = stateCatchUpSecond
+	m.menuList.Title = fmt.Sprintf("Catch Up - %s and...", m.catchUpParty)
+	m.menuList.ResetSelected()
+	return m, cmd
+}
+
+// updateCatchUpSecond lists what happened between the two parties' dates
+func (m AppModel) updateCatchUpSecond(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+	index := m.menuList.Index()
+	if index < 0 || index >= len(m.parties) {
+		return m, cmd
+	}
+	first := m.parties[commands.FindParty(m.parties, m.catchUpParty)]
+	second := m.parties[index]
+	if first.Name == second.Name {
+		m.statusMsg = ui.RenderError("Pick a different party.")
+		return m, cmd
+	}
+
+	events, err := commands.LoadEvents()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, cmd
+	}
+	events = commands.FilterEvents(events, m.eventFilter)
+
+	behind, ahead, between, err := commands.CatchUp(m.config, first, second, events)
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, cmd
+	}
+	if len(between) == 0 {
+		m.statusMsg = ui.RenderMuted(fmt.Sprintf("Nothing happened between %s (%s) and %s (%s).",
+			behind.Name, behind.CurrentDate, ahead.Name, ahead.CurrentDate))
+		return m, cmd
+	}
+
+	m.lookupEvents = between
+	m.state = stateLookupResults
+	m.menuList.SetItems(ui.EventListItems(between))
+	m.menuList.Title = fmt.Sprintf("What %s Missed (%d)", behind.Name, len(between))
+	m.statusMsg = ui.RenderMuted(fmt.Sprintf("Events after %s up to %s's date, %s.", behind.CurrentDate, ahead.Name, ahead.CurrentDate))
+	return m, cmd
+}
@@ -0,0 +1,251 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sksmith/gmcli/internal/commands"
+	"github.com/sksmith/gmcli/internal/config"
+	"github.com/sksmith/gmcli/internal/ui"
+)
+
+// startLogSession asks which calendar the session was played in
+func (m AppModel) startLogSession() AppModel {
+	if len(m.config.Calendars) == 0 {
+		m.statusMsg = ui.RenderError("No calendars available. Create a calendar first.")
+		return m
+	}
+
+	m.state = stateSessionCalendar
+	m.menuList.SetItems(ui.CalendarListItems(m.config.Calendars))
+	m.menuList.Title = "Select Calendar"
+	m.statusMsg = ""
+	return m
+}
+
+// updateSessionCalendar starts the session form for the selected calendar
+func (m AppModel) updateSessionCalendar(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+	item, ok := m.menuList.SelectedItem().(ui.Item)
+	if !ok {
+		return m, cmd
+	}
+
+	sessions, err := config.LoadSessions()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, cmd
+	}
+
+	next := commands.NextSessionNumber(sessions)
+	m.sessionInput = config.Session{Calendar: item.Title, Number: next}
+	m.sessionInputStage = 1
+	m.state = stateLogSession
+	m.input = ui.NewTextInput("Session number")
+	m.input.SetValue(strconv.Itoa(next))
+	m.statusMsg = ""
+	return m, cmd
+}
+
+// updateLogSession handles the multi-step session form. The in-game
+// dates default to where the last session ended and the campaign clock.
+func (m AppModel) updateLogSession(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	input := strings.TrimSpace(m.input.Value())
+	cal, err := commands.FindCalendar(m.config, m.sessionInput.Calendar)
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+
+	switch m.sessionInputStage {
+	case 1: // Number
+		number, err := strconv.Atoi(input)
+		if err != nil || number < 1 {
+			m.statusMsg = ui.RenderError("session number must be a positive number")
+			return m, nil
+		}
+		m.sessionInput.Number = number
+		m.input = ui.NewTextInput("Real-world date played (YYYY-MM-DD)")
+		m.input.SetValue(time.Now().Format("2006-01-02"))
+		m.sessionInputStage = 2
+
+	case 2: // Played
+		if _, err := time.Parse("2006-01-02", input); err != nil {
+			m.statusMsg = ui.RenderError("played date must be YYYY-MM-DD")
+			return m, nil
+		}
+		m.sessionInput.Played = input
+
+		start := cal.CurrentDate
+		sessions, err := config.LoadSessions()
+		if err != nil {
+			m.statusMsg = ui.RenderError(err.Error())
+			return m, nil
+		}
+		if last, ok := commands.LastSession(sessions, cal.Name); ok {
+			start = last.End
+		}
+		m.input = ui.NewTextInput("In-game start date (AAYYYY-MM-DD)")
+		m.input.SetValue(start)
+		m.sessionInputStage = 3
+
+	case 3: // Start
+		if _, err := commands.ValidateEventDate(input, cal, m.config.DaysInYear); err != nil {
+			m.statusMsg = ui.RenderError(err.Error())
+			return m, nil
+		}
+		m.sessionInput.Start = input
+
+		end := cal.CurrentDate
+		if end == "" {
+			end = input
+		}
+		m.input = ui.NewTextInput("In-game end date (AAYYYY-MM-DD)")
+		m.input.SetValue(end)
+		m.sessionInputStage = 4
+
+	case 4: // End
+		if _, err := commands.ValidateEventDate(input, cal, m.config.DaysInYear); err != nil {
+			m.statusMsg = ui.RenderError(err.Error())
+			return m, nil
+		}
+		m.sessionInput.End = input
+		m.input = ui.NewTextInput("Players present, or blank")
+		m.sessionInputStage = 5
+
+	case 5: // Players
+		m.sessionInput.Players = input
+
+		var session config.Session
+		run := func() error {
+			var err error
+			session, err = commands.LogSession(m.config, m.sessionInput)
+			return err
+		}
+		return m.track("Log session", run, func(m AppModel, err error) AppModel {
+			if err != nil {
+				m.statusMsg = ui.RenderError(fmt.Sprintf("Failed to log session: %v", err))
+				return m
+			}
+
+			m.sessionInputStage = 0
+			m = m.toMenu()
+			m.statusMsg = ui.RenderSuccess(fmt.Sprintf("Session %d logged (%s to %s) with its recap event.",
+				session.Number, session.Start, session.End))
+			return m
+		}), nil
+	}
+
+	m.statusMsg = ""
+	return m, cmd
+}
+
+// startTimeline asks which calendar's timeline to show
+func (m AppModel) startTimeline() AppModel {
+	if len(m.config.Calendars) == 0 {
+		m.statusMsg = ui.RenderError("No calendars available. Create a calendar first.")
+		return m
+	}
+
+	m.state = stateTimelineCalendar
+	m.menuList.SetItems(ui.CalendarListItems(m.config.Calendars))
+	m.menuList.Title = "Select Calendar"
+	m.statusMsg = ""
+	return m
+}
+
+// updateTimelineCalendar lists the sessions and events of the selected
+// calendar together
+func (m AppModel) updateTimelineCalendar(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+	item, ok := m.menuList.SelectedItem().(ui.Item)
+	if !ok {
+		return m, cmd
+	}
+	cal, err := commands.FindCalendar(m.config, item.Title)
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, cmd
+	}
+
+	events, err := commands.LoadEvents()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, cmd
+	}
+	sessions, err := config.LoadSessions()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, cmd
+	}
+
+	events = commands.FilterEvents(events, m.eventFilter)
+	m.timeline = commands.UnifiedTimeline(cal, m.config.DaysInYear, sessions, events)
+	if len(m.timeline) == 0 {
+		m.statusMsg = ui.RenderMuted(fmt.Sprintf("No sessions or events in %s yet.", cal.Name))
+		return m, cmd
+	}
+
+	timelineEvents := make([]config.Event, len(m.timeline))
+	timelineSessions := make([]*config.Session, len(m.timeline))
+	for i, entry := range m.timeline {
+		timelineEvents[i] = entry.Event
+		timelineSessions[i] = entry.Session
+	}
+
+	m.state = stateTimeline
+	m.menuList.SetItems(ui.TimelineListItems(timelineEvents, timelineSessions))
+	m.menuList.Title = fmt.Sprintf("%s Timeline", cal.Name)
+	m.statusMsg = ""
+	return m, cmd
+}
+
+// updateTimeline shows the details of the selected session or event
+func (m AppModel) updateTimeline(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+	index := m.menuList.Index()
+	if index < 0 || index >= len(m.timeline) {
+		return m, cmd
+	}
+	entry := m.timeline[index]
+
+	events, err := commands.LoadEvents()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, cmd
+	}
+	events = commands.FilterEvents(events, commands.EventFilter{PlayerView: m.eventFilter.PlayerView})
+
+	details := commands.GetEventDetails(entry.Event, commands.BuildLinkIndex(events).Connections(entry.Event))
+	if s := entry.Session; s != nil {
+		details = fmt.Sprintf("Session %d, played %s, covering %s to %s\n%s", s.Number, s.Played, s.Start, s.End, details)
+	}
+	m.statusMsg = details
+	return m, cmd
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sksmith/gmcli/internal/commands"
+	"github.com/sksmith/gmcli/internal/ui"
+)
+
+const statusPrompt = "Status: (s)cheduled, (o)ccurred, or blank to go by the clock"
+
+// startSetStatus asks which event to flag as scheduled or occurred
+func (m AppModel) startSetStatus() AppModel {
+	m.state = stateStatusEvent
+	m.input = ui.NewTextInput("Event name or ID")
+	m.statusMsg = ""
+	return m
+}
+
+// updateStatusEvent handles choosing the event whose status to set
+func (m AppModel) updateStatusEvent(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	events, err := commands.LoadEvents()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+	event, err := commands.FindEvent(events, strings.TrimSpace(m.input.Value()))
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+
+	current := event.Status
+	if current == "" {
+		current = "none"
+	}
+	m.statusEvent = event
+	m.state = stateStatusValue
+	m.input = ui.NewTextInput("Status: (s)cheduled, (o)ccurred, or blank to clear it")
+	m.statusMsg = fmt.Sprintf("Event: %s (%s), status: %s", event.Name, event.DateString(), current)
+	return m, cmd
+}
+
+// updateStatusValue sets the entered status on the event
+func (m AppModel) updateStatusValue(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	status, err := commands.ParseStatus(m.input.Value())
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m, nil
+	}
+	run := func() error {
+		return commands.SetEventStatus(m.statusEvent, status)
+	}
+	return m.track("Set the status of "+m.statusEvent.Name, run, func(m AppModel, err error) AppModel {
+		if err != nil {
+			m.statusMsg = ui.RenderError(err.Error())
+			return m
+		}
+
+		m = m.toMenu()
+		if status == "" {
+			m.statusMsg = ui.RenderSuccess(fmt.Sprintf("Cleared the status of '%s'.", m.statusEvent.Name))
+		} else {
+			m.statusMsg = ui.RenderSuccess(fmt.Sprintf("'%s' is now %s.", m.statusEvent.Name, status))
+		}
+		return m
+	}), nil
+}
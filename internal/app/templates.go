@@ -0,0 +1,119 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sksmith/gmcli/internal/commands"
+	"github.com/sksmith/gmcli/internal/config"
+	"github.com/sksmith/gmcli/internal/ui"
+)
+
+// startEventTemplate lets the user pick a template for the new event,
+// preselecting one named after its category. The picker is skipped when
+// only the default template exists.
+func (m AppModel) startEventTemplate() AppModel {
+	templates, err := commands.LoadTemplates()
+	if err != nil {
+		m.statusMsg = ui.RenderError(err.Error())
+		return m
+	}
+
+	m.templates = templates
+	if len(templates) <= 1 {
+		if len(templates) == 1 {
+			return m.selectEventTemplate(templates[0])
+		}
+		return m.startEventLocations()
+	}
+
+	m.state = stateEventTemplate
+	m.menuList.SetItems(ui.TemplateListItems(templates))
+	m.menuList.Title = "Choose a Template"
+	m.menuList.Select(0)
+	for i, tmpl := range templates {
+		if strings.EqualFold(tmpl.Name, m.eventData.Category) {
+			m.menuList.Select(i)
+		}
+	}
+	m.statusMsg = ""
+	return m
+}
+
+// updateEventTemplate handles choosing a template from the list
+func (m AppModel) updateEventTemplate(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if key.Matches(msg, m.keymap.Enter) {
+		index := m.menuList.Index()
+		if index >= 0 && index < len(m.templates) {
+			m = m.selectEventTemplate(m.templates[index])
+		}
+	}
+
+	return m, cmd
+}
+
+// selectEventTemplate records the chosen template and prompts for its
+// extra fields, if any
+func (m AppModel) selectEventTemplate(tmpl config.EventTemplate) AppModel {
+	m.eventData.Template = tmpl.Name
+	m.eventData.Fields = nil
+	m.templateFields = tmpl.Fields
+	m.templateFieldIndex = 0
+
+	if len(m.templateFields) == 0 {
+		return m.startEventLocations()
+	}
+	return m.promptTemplateField()
+}
+
+// promptTemplateField asks for the current template field
+func (m AppModel) promptTemplateField() AppModel {
+	field := m.templateFields[m.templateFieldIndex]
+
+	prompt := field.Prompt
+	if prompt == "" {
+		prompt = field.Name
+	}
+
+	m.state = stateEventField
+	m.input = ui.NewTextInput(prompt)
+	m.input.SetValue(field.Default)
+	m.statusMsg = ui.RenderMuted(fmt.Sprintf("Field %d of %d for the %s template", m.templateFieldIndex+1, len(m.templateFields), m.eventData.Template))
+	return m
+}
+
+// updateEventField stores a template field and moves to the next one
+func (m AppModel) updateEventField(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	if m.eventData.Fields == nil {
+		m.eventData.Fields = make(map[string]string)
+	}
+	field := m.templateFields[m.templateFieldIndex]
+	m.eventData.Fields[field.Name] = strings.TrimSpace(m.input.Value())
+
+	m.templateFieldIndex++
+	if m.templateFieldIndex < len(m.templateFields) {
+		return m.promptTemplateField(), cmd
+	}
+
+	m.statusMsg = ""
+	return m.startEventLocations(), cmd
+}
+
+// startEventLocations continues the event flow with its locations
+func (m AppModel) startEventLocations() AppModel {
+	m.state = stateEventLocations
+	m.input = ui.NewTextInput("Comma-separated location names, or blank")
+	return m
+}
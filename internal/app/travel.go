@@ -0,0 +1,230 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sksmith/gmcli/internal/commands"
+	"github.com/sksmith/gmcli/internal/ui"
+)
+
+// startTravel begins the travel calculator, in the active party's calendar
+// when a party is playing
+func (m AppModel) startTravel() AppModel {
+	if len(m.config.Calendars) == 0 {
+		m.statusMsg = ui.RenderError("No calendars available. Create a calendar first.")
+		return m
+	}
+
+	if index := commands.FindParty(m.parties, m.activeParty); index >= 0 {
+		for i, cal := range m.config.Calendars {
+			if cal.Name == m.parties[index].Calendar {
+				return m.startTravelIn(i)
+			}
+		}
+	}
+
+	m.state = stateTravelCalendar
+	m.menuList.SetItems(ui.CalendarListItems(m.config.Calendars))
+	m.menuList.Title = "Select Calendar"
+	m.statusMsg = ""
+	return m
+}
+
+// updateTravelCalendar starts the calculator for the selected calendar
+func (m AppModel) updateTravelCalendar(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.menuList, cmd = m.menuList.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+	item, ok := m.menuList.SelectedItem().(ui.Item)
+	if !ok {
+		return m, cmd
+	}
+
+	for i, cal := range m.config.Calendars {
+		if cal.Name == item.Title {
+			return m.startTravelIn(i), cmd
+		}
+	}
+	return m, cmd
+}
+
+// startTravelIn asks for the departure date, defaulting to the active
+// party's date or the calendar's clock
+func (m AppModel) startTravelIn(calIndex int) AppModel {
+	cal := m.config.Calendars[calIndex]
+
+	m.travelCalendarIndex = calIndex
+	m.travelPlan = commands.TravelPlan{}
+	m.travelInputStage = 1
+	m.state = stateTravel
+	m.input = ui.NewTextInput("Departure date (AAYYYY-MM-DD, today, +3d...)")
+	m.input.SetValue(cal.CurrentDate)
+	if party, ok := m.activePartyIn(cal.Name); ok {
+		m.input.SetValue(party.CurrentDate)
+	}
+	m.statusMsg = ""
+	return m
+}
+
+// updateTravel handles the multi-step travel calculator, then offers to
+// log the journey and move the clock to the arrival
+func (m AppModel) updateTravel(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if !key.Matches(msg, m.keymap.Enter) {
+		return m, cmd
+	}
+
+	input := strings.TrimSpace(m.input.Value())
+	cal := m.config.Calendars[m.travelCalendarIndex]
+	party, playing := m.activePartyIn(cal.Name)
+	modes := commands.TravelModes(m.config)
+
+	switch m.travelInputStage {
+	case 1: // Departure
+		events, err := commands.LoadEvents()
+		if err != nil {
+			m.statusMsg = ui.RenderError(err.Error())
+			return m, nil
+		}
+		today, hasToday := commands.CampaignToday(cal, m.config.DaysInYear, events)
+		if playing {
+			if date, err := commands.PartyDate(m.config, party); err == nil {
+				today, hasToday = date.DaysSinceZero, true
+			}
+		}
+		departure, err := commands.ResolveDate(input, cal, m.config.DaysInYear, today, hasToday, events)
+		if err != nil {
+			m.statusMsg = ui.RenderError(err.Error())
+			return m, nil
+		}
+		m.travelDeparture = departure
+		m.input = ui.NewTextInput("Distance in miles")
+		m.travelInputStage = 2
+
+	case 2: // Distance
+		distance, err := strconv.ParseFloat(input, 64)
+		if err != nil || distance < 0 {
+			m.statusMsg = ui.RenderError("distance must be a number of miles")
+			return m, nil
+		}
+		m.travelPlan.Distance = distance
+
+		names := make([]string, len(modes))
+		for i, mode := range modes {
+			names[i] = mode.Name
+		}
+		m.input = ui.NewTextInput(fmt.Sprintf("Travel mode (%s), blank for %s", strings.Join(names, ", "), names[0]))
+		m.travelInputStage = 3
+
+	case 3: // Mode
+		if input == "" {
+			input = modes[0].Name
+		}
+		mode, ok := commands.FindTravelMode(modes, input)
+		if !ok {
+			m.statusMsg = ui.RenderError(fmt.Sprintf("unknown travel mode '%s'", input))
+			return m, nil
+		}
+		m.travelPlan.Mode = mode.Name
+		m.input = ui.NewTextInput(fmt.Sprintf("Pace (%s), blank for %s", strings.Join(commands.PaceNames(mode), ", "), commands.DefaultPace))
+		m.travelInputStage = 4
+
+	case 4: // Pace
+		m.travelPlan.Pace = input
+		mode, _ := commands.FindTravelMode(modes, m.travelPlan.Mode)
+		m.input = ui.NewTextInput(fmt.Sprintf("Hours travelled per day, blank for %g", mode.HoursPerDay))
+		m.travelInputStage = 5
+
+	case 5: // Hours per day
+		if input != "" {
+			hours, err := strconv.ParseFloat(input, 64)
+			if err != nil {
+				m.statusMsg = ui.RenderError("hours per day must be a number")
+				return m, nil
+			}
+			m.travelPlan.HoursPerDay = hours
+		}
+		m.input = ui.NewTextInput("Rest days along the way, blank for none")
+		m.travelInputStage = 6
+
+	case 6: // Rest days
+		if input != "" {
+			rest, err := strconv.Atoi(input)
+			if err != nil {
+				m.statusMsg = ui.RenderError("rest days must be a whole number")
+				return m, nil
+			}
+			m.travelPlan.RestDays = rest
+		}
+
+		result, err := commands.PlanTravel(m.config, cal, m.travelDeparture, m.travelPlan)
+		if err != nil {
+			m.statusMsg = ui.RenderError(err.Error())
+			return m, nil
+		}
+		m.travelResult = result
+		m.input = ui.NewTextInput("Destination, to log departure and arrival events, or blank to skip")
+		m.travelInputStage = 7
+		m.statusMsg = ui.RenderHighlight(fmt.Sprintf("Arrives %s, %s after leaving.",
+			commands.DescribeDay(cal, result.Arrival), commands.PluralDays(result.TotalDays))) +
+			"\n" + ui.RenderMuted(result.String())
+		return m, nil
+
+	case 7: // Destination
+		whose := cal.Name
+		if playing {
+			whose = party.Name
+		}
+		askToAdvance := func(m AppModel) AppModel {
+			m.input = ui.NewTextInput(fmt.Sprintf("Advance the %s clock to %s? (y/N)", whose, m.travelResult.Arrival.DateString()))
+			m.travelInputStage = 8
+			return m
+		}
+		if input == "" {
+			return askToAdvance(m), nil
+		}
+
+		partyName := ""
+		if playing {
+			partyName = party.Name
+		}
+		run := func() error {
+			return commands.LogJourney(m.config, cal, m.travelResult, input, partyName)
+		}
+		return m.track("Log journey to "+input, run, func(m AppModel, err error) AppModel {
+			if err != nil {
+				m.statusMsg = ui.RenderError(err.Error())
+				return m
+			}
+			return askToAdvance(m)
+		}), nil
+
+	case 8: // Advance the clock
+		m.travelInputStage = 0
+		answer := strings.ToLower(input)
+		if answer != "y" && answer != "yes" {
+			m = m.toMenu()
+			m.statusMsg = ui.RenderSuccess(fmt.Sprintf("Arrival: %s.", m.travelResult.Arrival.DateString()))
+			return m, nil
+		}
+
+		m.clockCalendarIndex = m.travelCalendarIndex
+		m.clockParty = ""
+		if playing {
+			m.clockParty = party.Name
+		}
+		return m.advanceClock(m.travelResult.Arrival.DateString(), "Journey: "+m.travelResult.String()), nil
+	}
+
+	m.statusMsg = ""
+	return m, cmd
+}
@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sksmith/gmcli/internal/config"
+)
+
+// CharacterAge describes a character as of a given day.
+type CharacterAge struct {
+	Name    string
+	Species string
+	Age     int  // completed years; 0 before birth
+	Born    bool // false if the day is before the character's birth
+	Alive   bool
+	// Set when the character has outlived their species' lifespan
+	ExceedsLifespan bool
+}
+
+// Status returns a short human readable description of the character's
+// age on the day.
+func (a CharacterAge) Status() string {
+	switch {
+	case !a.Born:
+		return "not yet born"
+	case !a.Alive:
+		return fmt.Sprintf("dead (died aged %d)", a.Age)
+	case a.ExceedsLifespan:
+		return fmt.Sprintf("age %d (beyond their species' lifespan)", a.Age)
+	}
+	return fmt.Sprintf("age %d", a.Age)
+}
+
+// ValidateCharacter checks a character's fields against its calendar.
+func ValidateCharacter(ch config.Character, cfg config.Config) error {
+	if strings.TrimSpace(ch.Name) == "" {
+		return fmt.Errorf("character name cannot be empty")
+	}
+
+	cal, err := FindCalendar(cfg, ch.Calendar)
+	if err != nil {
+		return err
+	}
+
+	born, err := ValidateEventDate(ch.Born, cal, cfg.DaysInYear)
+	if err != nil {
+		return fmt.Errorf("birth date: %w", err)
+	}
+
+	if ch.Died != "" {
+		died, err := ValidateEventDate(ch.Died, cal, cfg.DaysInYear)
+		if err != nil {
+			return fmt.Errorf("death date: %w", err)
+		}
+		if died.DaysSinceZero < born.DaysSinceZero {
+			return fmt.Errorf("death date must not be before the birth date")
+		}
+	}
+
+	if ch.Lifespan < 0 {
+		return fmt.Errorf("lifespan cannot be negative")
+	}
+
+	return nil
+}
+
+// ValidateLifespan parses an optional lifespan in years
+func ValidateLifespan(input string) (int, error) {
+	if input == "" {
+		return 0, nil
+	}
+
+	years, err := strconv.Atoi(input)
+	if err != nil || years < 1 {
+		return 0, fmt.Errorf("lifespan must be a positive number of years")
+	}
+	return years, nil
+}
+
+// CreateCharacter validates a character and adds it to the registry
+func CreateCharacter(cfg config.Config, ch config.Character) error {
+	if err := ValidateCharacter(ch, cfg); err != nil {
+		return err
+	}
+
+	characters, err := config.LoadCharacters()
+	if err != nil {
+		return err
+	}
+
+	for _, existing := range characters {
+		if strings.EqualFold(existing.Name, ch.Name) {
+			return fmt.Errorf("character '%s' already exists", ch.Name)
+		}
+	}
+
+	return config.SaveCharacters(append(characters, ch))
+}
+
+// FindCalendar returns the calendar with the given name.
+func FindCalendar(cfg config.Config, name string) (config.Calendar, error) {
+	for _, cal := range cfg.Calendars {
+		if cal.Name == name {
+			return cal, nil
+		}
+	}
+	return config.Calendar{}, fmt.Errorf("calendar '%s' not found", name)
+}
+
+// AgeOn computes a character's age on the given day of their calendar.
+func AgeOn(ch config.Character, cal config.Calendar, daysInYear int, day int) (CharacterAge, error) {
+	age := CharacterAge{Name: ch.Name, Species: ch.Species}
+
+	born, err := ValidateEventDate(ch.Born, cal, daysInYear)
+	if err != nil {
+		return age, fmt.Errorf("%s birth date: %w", ch.Name, err)
+	}
+	if day < born.DaysSinceZero {
+		return age, nil
+	}
+
+	age.Born = true
+	age.Alive = true
+	lastDay := day
+
+	if ch.Died != "" {
+		died, err := ValidateEventDate(ch.Died, cal, daysInYear)
+		if err != nil {
+			return age, fmt.Errorf("%s death date: %w", ch.Name, err)
+		}
+		if day > died.DaysSinceZero {
+			age.Alive = false
+			lastDay = died.DaysSinceZero
+		}
+	}
+
+	// Every year has the same length, so whole years elapsed is the age
+	age.Age = (lastDay - born.DaysSinceZero) / daysInYear
+	age.ExceedsLifespan = age.Alive && ch.Lifespan > 0 && age.Age > ch.Lifespan
+
+	return age, nil
+}
+
+// CharactersAliveOn returns the characters of a calendar alive on the
+// given day along with their ages. Characters with invalid dates are
+// skipped.
+func CharactersAliveOn(characters []config.Character, cal config.Calendar, daysInYear int, day int) []CharacterAge {
+	var alive []CharacterAge
+	for _, ch := range characters {
+		if ch.Calendar != cal.Name {
+			continue
+		}
+		age, err := AgeOn(ch, cal, daysInYear, day)
+		if err == nil && age.Born && age.Alive {
+			alive = append(alive, age)
+		}
+	}
+	return alive
+}
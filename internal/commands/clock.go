@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sksmith/gmcli/internal/config"
+)
+
+// advancePattern matches an amount of time without a sign, e.g. "3 days"
+var advancePattern = regexp.MustCompile(`^\d+\s*[a-zA-Z]+$`)
+
+// CurrentDate returns the calendar's current campaign date. ok is false
+// when the clock hasn't been set.
+func CurrentDate(cal config.Calendar, daysInYear int) (config.Event, bool, error) {
+	if cal.CurrentDate == "" {
+		return config.Event{}, false, nil
+	}
+
+	date, err := ValidateEventDate(cal.CurrentDate, cal, daysInYear)
+	if err != nil {
+		return config.Event{}, false, fmt.Errorf("current date of %s: %w", cal.Name, err)
+	}
+	return date, true, nil
+}
+
+// AdvanceClock moves a calendar's current date and saves the config. The
+// input is an amount of time such as "3 days", "2 weeks" or "-1 month", or
+// any date ResolveDate understands, e.g. "DF0100-06-01" or "next Forge Fire
+// 12". It returns the previous and the new date; the previous date is the
+// new one when the clock wasn't set yet.
+func AdvanceClock(cfg *config.Config, calIndex int, input string, events []config.Event) (config.Event, config.Event, error) {
+	if calIndex < 0 || calIndex >= len(cfg.Calendars) {
+		return config.Event{}, config.Event{}, fmt.Errorf("calendar not found")
+	}
+	cal := cfg.Calendars[calIndex]
+
+	previous, date, err := advanceDate(cal, cfg.DaysInYear, cal.CurrentDate, input, events)
+	if err != nil {
+		return config.Event{}, config.Event{}, err
+	}
+
+	cfg.Calendars[calIndex].CurrentDate = date.DateString()
+	if err := config.Save(*cfg); err != nil {
+		cfg.Calendars[calIndex].CurrentDate = cal.CurrentDate
+		return config.Event{}, config.Event{}, err
+	}
+
+	return previous, date, nil
+}
+
+// advanceDate moves a clock reading as AdvanceClock describes. Relative
+// input counts from current, or from the campaign's date when it's unset.
+func advanceDate(cal config.Calendar, daysInYear int, current, input string, events []config.Event) (config.Event, config.Event, error) {
+	input = strings.TrimSpace(input)
+	if advancePattern.MatchString(input) {
+		input = "+" + input
+	}
+
+	// A current date the calendar no longer has counts as unset, so the
+	// clock can be moved to a valid one
+	var previous config.Event
+	hasPrevious := false
+	if current != "" {
+		if date, err := ValidateEventDate(current, cal, daysInYear); err == nil {
+			previous, hasPrevious = date, true
+		}
+	}
+
+	today, hasToday := previous.DaysSinceZero, hasPrevious
+	if !hasPrevious {
+		today, hasToday = CampaignToday(cal, daysInYear, events)
+	}
+	date, err := ResolveDate(input, cal, daysInYear, today, hasToday, events)
+	if err != nil {
+		return config.Event{}, config.Event{}, err
+	}
+	if date.IsApproximate() {
+		return config.Event{}, config.Event{}, fmt.Errorf("the current date must be an exact day")
+	}
+
+	if !hasPrevious {
+		previous = date
+	}
+	return previous, date, nil
+}
+
+// DescribeDay words an exact date with its weekday and month name when the
+// calendar has them, e.g. "Moonday, 9 Forge Fire DF0100".
+func DescribeDay(cal config.Calendar, date config.Event) string {
+	month := MonthName(cal, date.Month)
+	if month == "" {
+		return date.DateString()
+	}
+
+	described := fmt.Sprintf("%d %s %s%04d", date.Day, month, date.AgeAbbrev, date.Year)
+	if weekday := Weekday(cal, date.DaysSinceZero); weekday != "" {
+		described = weekday + ", " + described
+	}
+	return described
+}
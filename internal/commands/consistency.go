@@ -0,0 +1,216 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sksmith/gmcli/internal/config"
+)
+
+// Kinds of consistency issues
+const (
+	IssueUnknownCalendar = "unknown calendar"
+	IssueInvalidDate     = "invalid date"
+	IssueStaleDays       = "stale days"
+	IssueBeforeBirth     = "before birth"
+	IssuePosthumous      = "posthumous"
+	IssueOverlap         = "overlap"
+)
+
+// Issue is a continuity problem found in the timeline.
+type Issue struct {
+	Kind    string
+	Event   config.Event
+	Message string
+}
+
+// newIssue formats an issue's message
+func newIssue(kind string, event config.Event, format string, args ...interface{}) Issue {
+	return Issue{Kind: kind, Event: event, Message: fmt.Sprintf(format, args...)}
+}
+
+// CheckConsistency analyses every event against the calendars, characters
+// and locations and returns the problems found, in chronological order.
+// Recurring events are checked on their first occurrence only.
+func CheckConsistency(cfg config.Config, events []config.Event, characters []config.Character, locations []config.Location) []Issue {
+	var issues []Issue
+	var valid []config.Event
+
+	for _, event := range events {
+		cal, err := FindCalendar(cfg, event.CalendarName)
+		if err != nil {
+			issues = append(issues, newIssue(IssueUnknownCalendar, event, "calendar '%s' no longer exists", event.CalendarName))
+			continue
+		}
+
+		dateIssues := checkEventDate(cfg, cal, event)
+		issues = append(issues, dateIssues...)
+		if len(dateIssues) == 0 {
+			valid = append(valid, event)
+		}
+	}
+
+	issues = append(issues, checkLifespans(cfg, valid, characters)...)
+	issues = append(issues, checkOverlaps(valid, locations)...)
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Event.DaysSinceZero < issues[j].Event.DaysSinceZero
+	})
+	return issues
+}
+
+// checkEventDate checks that an event's dates exist in its calendar and
+// that the stored day counts still match them
+func checkEventDate(cfg config.Config, cal config.Calendar, event config.Event) []Issue {
+	var issues []Issue
+
+	check := func(label, age string, year, month, day, stored int, precision string) {
+		if err := checkDateParts(cal, age, month, day, precision); err != nil {
+			issues = append(issues, newIssue(IssueInvalidDate, event, "%s: %v", label, err))
+			return
+		}
+
+		// Unknown parts of approximate dates count from the start of the period
+		if month == 0 {
+			month = 1
+		}
+		if day == 0 {
+			day = 1
+		}
+		if want := DaysFromDate(cal, cfg.DaysInYear, year, month, day); want != stored {
+			issues = append(issues, newIssue(IssueStaleDays, event, "%s is stored as day %d but is day %d under the current config", label, stored, want))
+		}
+	}
+
+	check("date", event.AgeAbbrev, event.Year, event.Month, event.Day, event.DaysSinceZero, event.Precision)
+	if event.EndDaysSinceZero != 0 {
+		check("end date", event.EndAgeAbbrev, event.EndYear, event.EndMonth, event.EndDay, event.EndDaysSinceZero, "")
+	}
+
+	return issues
+}
+
+// checkDateParts checks that an age, month and day exist in a calendar.
+// Approximate dates may leave the parts they don't know as zero.
+func checkDateParts(cal config.Calendar, age string, month, day int, precision string) error {
+	found := false
+	for _, a := range cal.Ages {
+		if a.Abbreviation == age {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("age '%s' is not in calendar '%s'", age, cal.Name)
+	}
+
+	monthKnown := precision != config.PrecisionYear && precision != config.PrecisionCirca
+	dayKnown := monthKnown && precision != config.PrecisionMonth
+	if !monthKnown {
+		return nil
+	}
+	if month < 1 || month > len(cal.Months) {
+		return fmt.Errorf("month %d is not in calendar '%s'", month, cal.Name)
+	}
+	if dayKnown && (day < 1 || day > cal.Months[month-1].Days) {
+		return fmt.Errorf("%s has no day %d", cal.Months[month-1].Name, day)
+	}
+	return nil
+}
+
+// checkLifespans flags characters involved in events before they were
+// born or after they died
+func checkLifespans(cfg config.Config, events []config.Event, characters []config.Character) []Issue {
+	var issues []Issue
+
+	for _, event := range events {
+		cal, _ := FindCalendar(cfg, event.CalendarName)
+		first, last := event.Window()
+
+		for _, name := range event.Characters {
+			ch, ok := FindCharacter(characters, name)
+			if !ok {
+				continue
+			}
+			chCal, err := FindCalendar(cfg, ch.Calendar)
+			if err != nil {
+				continue
+			}
+
+			// Compare on the event's calendar
+			if born, err := ValidateEventDate(ch.Born, chCal, cfg.DaysInYear); err == nil {
+				if bornDay := ConvertDays(chCal, cal, born.DaysSinceZero); last < bornDay {
+					issues = append(issues, newIssue(IssueBeforeBirth, event, "%s is involved but isn't born until %s", ch.Name, ch.Born))
+				}
+			}
+			if ch.Died == "" {
+				continue
+			}
+			if died, err := ValidateEventDate(ch.Died, chCal, cfg.DaysInYear); err == nil {
+				if diedDay := ConvertDays(chCal, cal, died.DaysSinceZero); first > diedDay {
+					issues = append(issues, newIssue(IssuePosthumous, event, "%s is involved but died on %s", ch.Name, ch.Died))
+				}
+			}
+		}
+	}
+
+	return issues
+}
+
+// checkOverlaps flags characters involved in events in unrelated places on
+// overlapping days. Places nested inside each other don't conflict, nor
+// do events without locations or with approximate dates.
+func checkOverlaps(events []config.Event, locations []config.Location) []Issue {
+	var issues []Issue
+
+	for i, a := range events {
+		if a.IsApproximate() || len(a.Locations) == 0 {
+			continue
+		}
+		for _, b := range events[i+1:] {
+			if b.IsApproximate() || len(b.Locations) == 0 || a.CalendarName != b.CalendarName {
+				continue
+			}
+			if b.DaysSinceZero > a.LastDay() || a.DaysSinceZero > b.LastDay() {
+				continue
+			}
+			if placesCompatible(locations, a.Locations, b.Locations) {
+				continue
+			}
+
+			for _, name := range a.Characters {
+				if !containsFold(b.Characters, name) {
+					continue
+				}
+				issues = append(issues, newIssue(IssueOverlap, b, "%s is also at %s for '%s' (%s)",
+					name, strings.Join(a.Locations, ", "), a.Name, a.DateString()))
+			}
+		}
+	}
+
+	return issues
+}
+
+// placesCompatible reports whether any place of one event is the same as,
+// or nested within, a place of the other
+func placesCompatible(locations []config.Location, a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if IsWithin(locations, x, y) || IsWithin(locations, y, x) || strings.EqualFold(x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsFold reports whether list holds s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
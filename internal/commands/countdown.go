@@ -0,0 +1,235 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sksmith/gmcli/internal/config"
+)
+
+// DefaultWarnDays is how many days ahead of its target a countdown starts
+// warning when it doesn't set its own
+const DefaultWarnDays = 7
+
+// Countdown states
+const (
+	CountdownPending = "pending"
+	CountdownNear    = "near" // within its warning days
+	CountdownDue     = "due"  // due today
+	CountdownPassed  = "passed"
+	CountdownUnknown = "unknown" // no current date or missing target
+)
+
+// DigestDeadline is the digest entry kind of countdown warnings
+const DigestDeadline = "deadline"
+
+// CountdownStatus is where a countdown stands on the campaign clock.
+type CountdownStatus struct {
+	Countdown config.Countdown
+	Target    config.Event // the target date, or the event counted down to, even when unknown
+	Remaining int          // days left, negative once passed
+	State     string
+	Problem   string // why the state is unknown
+}
+
+// String words the days remaining, e.g. "23 days left".
+func (s CountdownStatus) String() string {
+	switch s.State {
+	case CountdownUnknown:
+		return s.Problem
+	case CountdownDue:
+		return "due today"
+	case CountdownPassed:
+		return "passed " + PluralDays(-s.Remaining) + " ago"
+	}
+	return PluralDays(s.Remaining) + " left"
+}
+
+// PluralDays formats a number of days, e.g. "1 day" or "3 days".
+func PluralDays(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+// warnDays returns how many days ahead a countdown warns
+func warnDays(c config.Countdown) int {
+	if c.WarnDays > 0 {
+		return c.WarnDays
+	}
+	return DefaultWarnDays
+}
+
+// countdownTarget returns the date a countdown is due on. The event
+// counted down to is returned even when it has no exact date.
+func countdownTarget(cfg config.Config, cal config.Calendar, c config.Countdown, events []config.Event) (config.Event, error) {
+	if c.Event != "" {
+		event, err := FindEvent(events, c.Event)
+		if err != nil {
+			return config.Event{}, err
+		}
+		if event.IsApproximate() {
+			return event, fmt.Errorf("event '%s' has no exact date", event.Name)
+		}
+		return event, nil
+	}
+	return ValidateEventDate(c.Target, cal, cfg.DaysInYear)
+}
+
+// CreateCountdown validates a countdown and adds it to the countdowns. The
+// target is an event name or ID, or a date ResolveDate understands such as
+// "DF0100-06-02" or "+23 days".
+func CreateCountdown(cfg config.Config, c config.Countdown, target string, events []config.Event) error {
+	countdowns, err := config.LoadCountdowns()
+	if err != nil {
+		return err
+	}
+
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" {
+		return fmt.Errorf("countdown name cannot be empty")
+	}
+	for _, existing := range countdowns {
+		if strings.EqualFold(existing.Name, c.Name) {
+			return fmt.Errorf("countdown '%s' already exists", c.Name)
+		}
+	}
+	if c.WarnDays < 0 {
+		return fmt.Errorf("warning days cannot be negative")
+	}
+
+	cal, err := FindCalendar(cfg, c.Calendar)
+	if err != nil {
+		return err
+	}
+
+	target = strings.TrimSpace(target)
+	if event, err := FindEvent(events, target); err == nil && event.CalendarName == cal.Name {
+		if event.IsApproximate() {
+			return fmt.Errorf("event '%s' has no exact date", event.Name)
+		}
+		c.Event = event.ID
+	} else {
+		today, hasToday := CampaignToday(cal, cfg.DaysInYear, events)
+		date, err := ResolveDate(target, cal, cfg.DaysInYear, today, hasToday, events)
+		if err != nil {
+			return err
+		}
+		if date.IsApproximate() {
+			return fmt.Errorf("the target must be an exact day")
+		}
+		c.Target = date.DateString()
+	}
+
+	return config.SaveCountdowns(append(countdowns, c))
+}
+
+// CountdownStatuses works out how many days each countdown has left on its
+// calendar's campaign date, most urgent first. Passed countdowns come
+// first and those that can't be worked out last.
+func CountdownStatuses(cfg config.Config, countdowns []config.Countdown, events []config.Event) []CountdownStatus {
+	statuses := make([]CountdownStatus, 0, len(countdowns))
+	for _, c := range countdowns {
+		statuses = append(statuses, countdownStatus(cfg, c, events))
+	}
+
+	sort.SliceStable(statuses, func(i, j int) bool {
+		ui, uj := statuses[i].State == CountdownUnknown, statuses[j].State == CountdownUnknown
+		if ui != uj {
+			return uj
+		}
+		return statuses[i].Remaining < statuses[j].Remaining
+	})
+	return statuses
+}
+
+// countdownStatus works out a single countdown's status
+func countdownStatus(cfg config.Config, c config.Countdown, events []config.Event) CountdownStatus {
+	status := CountdownStatus{Countdown: c, State: CountdownUnknown}
+
+	cal, err := FindCalendar(cfg, c.Calendar)
+	if err != nil {
+		status.Problem = err.Error()
+		return status
+	}
+	target, err := countdownTarget(cfg, cal, c, events)
+	status.Target = target
+	if err != nil {
+		status.Problem = err.Error()
+		return status
+	}
+
+	today, ok := CampaignToday(cal, cfg.DaysInYear, events)
+	if !ok {
+		status.Problem = fmt.Sprintf("%s has no current date", cal.Name)
+		return status
+	}
+
+	status.Remaining = target.DaysSinceZero - today
+	switch {
+	case status.Remaining < 0:
+		status.State = CountdownPassed
+	case status.Remaining == 0:
+		status.State = CountdownDue
+	case status.Remaining <= warnDays(c):
+		status.State = CountdownNear
+	default:
+		status.State = CountdownPending
+	}
+	return status
+}
+
+// CountdownDigest returns warnings for the countdowns of a calendar after
+// its clock moved from one day to a later one: those that fell due or
+// passed on the way, and those now within their warning days.
+func CountdownDigest(cfg config.Config, cal config.Calendar, from, to int, countdowns []config.Countdown, events []config.Event) []DigestEntry {
+	if to <= from {
+		return nil
+	}
+
+	var entries []DigestEntry
+	for _, c := range countdowns {
+		if c.Calendar != cal.Name {
+			continue
+		}
+		target, err := countdownTarget(cfg, cal, c, events)
+		if err != nil {
+			continue
+		}
+
+		remaining := target.DaysSinceZero - to
+		var message string
+		switch {
+		case target.DaysSinceZero <= from:
+			continue // passed before this advance
+		case remaining == 0:
+			message = fmt.Sprintf("%s is due today", c.Name)
+		case remaining < 0:
+			message = fmt.Sprintf("%s passed %s ago", c.Name, PluralDays(-remaining))
+		case remaining <= warnDays(c):
+			message = fmt.Sprintf("%s: %s left", c.Name, PluralDays(remaining))
+		default:
+			continue
+		}
+
+		entry := DigestEntry{
+			Kind:    DigestDeadline,
+			Days:    target.DaysSinceZero,
+			Date:    target.DateString(),
+			Message: message,
+		}
+		// Countdowns to an event carry it, so player view can hide them
+		if c.Event != "" {
+			target := target
+			entry.Event = &target
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Days < entries[j].Days
+	})
+	return entries
+}
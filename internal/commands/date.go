@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sksmith/gmcli/internal/config"
+)
+
+// AgeForYear returns the age a year falls in, walking the calendar's ages
+// in order from its start year. Years outside every age fall back to the
+// nearest one.
+func AgeForYear(cal config.Calendar, year int) config.Age {
+	if len(cal.Ages) == 0 {
+		return config.Age{}
+	}
+
+	start := cal.StartYear
+	for _, age := range cal.Ages {
+		if year < start+age.Length {
+			return age
+		}
+		start += age.Length
+	}
+
+	return cal.Ages[len(cal.Ages)-1]
+}
+
+// DaysFromDate returns the number of days since 0 for a validated date.
+func DaysFromDate(cal config.Calendar, daysInYear int, year, month, day int) int {
+	totalDays := year * daysInYear
+
+	// Sum days of months before the selected month
+	for i := 0; i < month-1 && i < len(cal.Months); i++ {
+		totalDays += cal.Months[i].Days
+	}
+
+	return totalDays + day
+}
+
+// DateFromDays converts a days-since-0 value back into a calendar date.
+// The returned event only has its calendar and date fields populated.
+func DateFromDays(cal config.Calendar, daysInYear int, days int) (config.Event, error) {
+	if daysInYear <= 0 {
+		return config.Event{}, fmt.Errorf("days in year must be positive")
+	}
+
+	// Days are 1-based within a year, so shift before dividing
+	year := (days - 1) / daysInYear
+	if days-1 < 0 && (days-1)%daysInYear != 0 {
+		year--
+	}
+	dayOfYear := days - year*daysInYear
+
+	for i, month := range cal.Months {
+		if dayOfYear <= month.Days {
+			return config.Event{
+				CalendarName:   cal.Name,
+				CalendarAbbrev: cal.Abbreviation,
+				AgeAbbrev:      AgeForYear(cal, year).Abbreviation,
+				Year:           year,
+				Month:          i + 1,
+				Day:            dayOfYear,
+				DaysSinceZero:  days,
+			}, nil
+		}
+		dayOfYear -= month.Days
+	}
+
+	return config.Event{}, fmt.Errorf("day %d falls outside the months of calendar '%s'", days, cal.Name)
+}
+
+// MoonPhases names the eight phases of a moon, starting from new moon
+var MoonPhases = []string{
+	"new moon", "waxing crescent", "first quarter", "waxing gibbous",
+	"full moon", "waning gibbous", "last quarter", "waning crescent",
+}
+
+// mod returns a modulo b, always in the range [0, b)
+func mod(a, b int) int {
+	return ((a % b) + b) % b
+}
+
+// Weekday returns the name of the weekday a day falls on, or "" if the
+// calendar has no weeks.
+func Weekday(cal config.Calendar, days int) string {
+	if len(cal.Weekdays) == 0 {
+		return ""
+	}
+	return cal.Weekdays[mod(days, len(cal.Weekdays))]
+}
+
+// MoonPhase returns the phase of a moon on the given day.
+func MoonPhase(moon config.Moon, days int) string {
+	if moon.Cycle <= 0 {
+		return ""
+	}
+
+	age := mod(days-moon.Offset, moon.Cycle)
+	index := (age*len(MoonPhases)*2 + moon.Cycle) / (moon.Cycle * 2) // round to nearest phase
+	return MoonPhases[index%len(MoonPhases)]
+}
+
+// MonthName returns the name of a month, or "" if it doesn't exist.
+func MonthName(cal config.Calendar, month int) string {
+	if month < 1 || month > len(cal.Months) {
+		return ""
+	}
+	return cal.Months[month-1].Name
+}
+
+// Season returns the season a month belongs to, or "" if none is set.
+func Season(cal config.Calendar, month int) string {
+	if month < 1 || month > len(cal.Months) {
+		return ""
+	}
+	return cal.Months[month-1].Season
+}
+
+// AgeName returns the full name of an age from its abbreviation.
+func AgeName(cal config.Calendar, abbrev string) string {
+	for _, age := range cal.Ages {
+		if age.Abbreviation == abbrev {
+			return age.Name
+		}
+	}
+	return abbrev
+}
+
+// ConvertDays translates a day count from one calendar to another using
+// their epoch offsets.
+func ConvertDays(from, to config.Calendar, days int) int {
+	return days + from.EpochOffset - to.EpochOffset
+}
+
+// FormatDate renders a day using a layout with the placeholders {age},
+// {ageName}, {year}, {month}, {monthName}, {day}, {weekday} and {season}.
+func FormatDate(cal config.Calendar, daysInYear int, layout string, days int) (string, error) {
+	date, err := DateFromDays(cal, daysInYear, days)
+	if err != nil {
+		return "", err
+	}
+
+	replacer := strings.NewReplacer(
+		"{ageName}", AgeName(cal, date.AgeAbbrev),
+		"{age}", date.AgeAbbrev,
+		"{year}", fmt.Sprintf("%04d", date.Year),
+		"{monthName}", MonthName(cal, date.Month),
+		"{month}", fmt.Sprintf("%02d", date.Month),
+		"{day}", fmt.Sprintf("%02d", date.Day),
+		"{weekday}", Weekday(cal, days),
+		"{season}", Season(cal, date.Month),
+	)
+	return replacer.Replace(layout), nil
+}
+
+// DescribeDate words an event's date using the calendar's month names,
+// e.g. "sometime in Forge Fire DF0100" for a month-precision date.
+func DescribeDate(cal config.Calendar, event config.Event) string {
+	if event.Precision == config.PrecisionMonth {
+		if name := MonthName(cal, event.Month); name != "" {
+			return fmt.Sprintf("sometime in %s %s%04d", name, event.AgeAbbrev, event.Year)
+		}
+	}
+	return event.DateDescription()
+}
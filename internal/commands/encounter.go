@@ -0,0 +1,249 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sksmith/gmcli/internal/config"
+	"gopkg.in/yaml.v2"
+)
+
+// EncounterTemplateName is the template logged encounters are rendered
+// with, when the workspace has it
+const EncounterTemplateName = "encounter"
+
+// TimesOfDay lists the suggested times of day to roll for
+var TimesOfDay = []string{"dawn", "day", "dusk", "night"}
+
+// LoadEncounterTables reads every encounter table in the encounters
+// directory, sorted by name. Tables without a name are named after their
+// file.
+func LoadEncounterTables() ([]config.EncounterTable, error) {
+	paths, err := filepath.Glob(filepath.Join(config.EncountersDir, "*.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list encounter tables: %w", err)
+	}
+
+	var tables []config.EncounterTable
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encounter table: %w", err)
+		}
+
+		var table config.EncounterTable
+		if err := yaml.Unmarshal(data, &table); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if table.Name == "" {
+			table.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		table.Path = path
+		tables = append(tables, table)
+	}
+
+	sort.SliceStable(tables, func(i, j int) bool {
+		return tables[i].Name < tables[j].Name
+	})
+	return tables, nil
+}
+
+// EncounterConditions describe the moment an encounter is rolled for.
+type EncounterConditions struct {
+	Date     config.Event
+	Season   string
+	Time     string
+	Location string
+	Moons    map[string]string // phase of each moon by name
+}
+
+// NewEncounterConditions works out the season and moon phases of a date.
+func NewEncounterConditions(cal config.Calendar, date config.Event, timeOfDay, location string) EncounterConditions {
+	cond := EncounterConditions{
+		Date:     date,
+		Season:   Season(cal, date.Month),
+		Time:     strings.TrimSpace(timeOfDay),
+		Location: strings.TrimSpace(location),
+		Moons:    make(map[string]string),
+	}
+	for _, moon := range cal.Moons {
+		cond.Moons[moon.Name] = MoonPhase(moon, date.DaysSinceZero)
+	}
+	return cond
+}
+
+// String describes the conditions, e.g. "Winter, night, Brindle, Selune
+// full moon".
+func (c EncounterConditions) String() string {
+	var parts []string
+	for _, part := range []string{c.Season, c.Time, c.Location} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	names := make([]string, 0, len(c.Moons))
+	for name := range c.Moons {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s %s", name, c.Moons[name]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// modifierMatches reports whether every condition a modifier sets holds
+func modifierMatches(mod config.EncounterModifier, cond EncounterConditions, locations []config.Location) bool {
+	if mod.Season != "" && !strings.EqualFold(mod.Season, cond.Season) {
+		return false
+	}
+	if mod.Time != "" && !strings.EqualFold(mod.Time, cond.Time) {
+		return false
+	}
+	if mod.Location != "" {
+		if cond.Location == "" {
+			return false
+		}
+		if !strings.EqualFold(mod.Location, cond.Location) && !IsWithin(locations, cond.Location, mod.Location) {
+			return false
+		}
+	}
+	if mod.Moon != "" && !moonMatches(mod.Moon, cond.Moons) {
+		return false
+	}
+	return true
+}
+
+// moonMatches reports whether any moon is in the phase, or for "Moon:
+// phase" whether that moon is
+func moonMatches(want string, moons map[string]string) bool {
+	name, phase, named := strings.Cut(want, ":")
+	if !named {
+		phase = name
+	}
+	phase = strings.TrimSpace(phase)
+
+	for moon, current := range moons {
+		if named && !strings.EqualFold(moon, strings.TrimSpace(name)) {
+			continue
+		}
+		if strings.EqualFold(current, phase) {
+			return true
+		}
+	}
+	return false
+}
+
+// EncounterWeights returns the weight of each entry of a table under the
+// given conditions: its base weight, 1 when unset, times the multiplier of
+// every modifier that applies.
+func EncounterWeights(table config.EncounterTable, cond EncounterConditions, locations []config.Location) []float64 {
+	weights := make([]float64, len(table.Entries))
+	for i, entry := range table.Entries {
+		weight := entry.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		for _, mod := range entry.Modifiers {
+			if modifierMatches(mod, cond, locations) {
+				weight *= mod.Multiplier
+			}
+		}
+		if weight < 0 {
+			weight = 0
+		}
+		weights[i] = weight
+	}
+	return weights
+}
+
+// EncounterRoll is the result of rolling on an encounter table.
+type EncounterRoll struct {
+	Table      string
+	Entry      config.EncounterEntry
+	Seed       int64
+	Chance     float64 // probability of this result under the conditions
+	Conditions EncounterConditions
+}
+
+// NewSeed returns a seed for a roll when the user doesn't give one.
+func NewSeed() int64 {
+	return time.Now().UnixNano() % 1000000
+}
+
+// RollEncounter picks an entry of a table at random, following the weights
+// the conditions give. The same seed and conditions always give the same
+// result.
+func RollEncounter(table config.EncounterTable, cond EncounterConditions, locations []config.Location, seed int64) (EncounterRoll, error) {
+	weights := EncounterWeights(table, cond, locations)
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	if total <= 0 {
+		return EncounterRoll{}, fmt.Errorf("nothing on '%s' can happen under these conditions", table.Name)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	pick := rng.Float64() * total
+	// Rounding can leave the pick past the last weight, so fall back to
+	// the last entry that can happen
+	rolled := -1
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		rolled = i
+		if pick < w {
+			break
+		}
+		pick -= w
+	}
+	return EncounterRoll{
+		Table:      table.Name,
+		Entry:      table.Entries[rolled],
+		Seed:       seed,
+		Chance:     weights[rolled] / total,
+		Conditions: cond,
+	}, nil
+}
+
+// LogEncounter records a roll as an event on the date it was rolled for,
+// attributed to the party if one is given.
+func LogEncounter(cfg config.Config, cal config.Calendar, roll EncounterRoll, locations []config.Location, party string) error {
+	event := roll.Conditions.Date
+	event.ID = NewEventID()
+	event.Name = "Encounter: " + roll.Entry.Result
+	event.Party = party
+	event.Tags = []string{"encounter"}
+	event.Body = fmt.Sprintf("%s, rolled on %s.", roll.Entry.Result, roll.Table)
+	if loc, ok := FindLocation(locations, roll.Conditions.Location); ok {
+		event.Locations = []string{loc.Name}
+	}
+	event.Fields = map[string]string{
+		"table":       roll.Table,
+		"seed":        strconv.FormatInt(roll.Seed, 10),
+		"time_of_day": roll.Conditions.Time,
+		"conditions":  roll.Conditions.String(),
+	}
+	if fileExists(filepath.Join(config.TemplatesDir, EncounterTemplateName+templateSuffix)) {
+		event.Template = EncounterTemplateName
+	}
+
+	err := CreateEvent(cfg, cal, event)
+	if errors.Is(err, ErrEventExists) {
+		err = CreateEventRenamed(cfg, cal, event)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to log encounter: %w", err)
+	}
+	return nil
+}
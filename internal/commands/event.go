@@ -1,31 +1,50 @@
 package commands
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
-	"text/template"
 
 	"github.com/sksmith/gmcli/internal/config"
 )
 
+// strictDatePattern matches exact AAYYYY-MM-DD dates: a two character age
+// abbreviation, a four digit year and two digit month and day
+var strictDatePattern = regexp.MustCompile(`^([A-Za-z0-9]{2})(\d{4})-(\d{2})-(\d{2})$`)
+
+// canonicalDatePattern matches AAYYYY-MM-DD dates as well as the shortened
+// AAYYYY-MM and AAYYYY forms
+var canonicalDatePattern = regexp.MustCompile(`^([A-Za-z0-9]{2})(\d{4})(?:-(\d{2})(?:-(\d{2}))?)?$`)
+
+// looseDatePattern matches the same forms with unpadded numbers, e.g.
+// DF100-5-4. Years may have any number of digits.
+var looseDatePattern = regexp.MustCompile(`^([A-Za-z]+)(\d+)(?:-(\d{1,2})(?:-(\d{1,2}))?)?$`)
+
+// circaPattern matches circa dates such as "c. DF0100 ±10", "circa DF0100
+// +/- 10 years" or "~DF0100"
+var circaPattern = regexp.MustCompile(`^(?i:circa\s*|c\.\s*|c\s+|~\s*)([A-Za-z]+\d+)\s*(?:(?:±|\+/?-)\s*(\d+)(?:\s*years?)?)?$`)
+
 // ValidateEventDate validates an event date in format AAYYYY-MM-DD
 func ValidateEventDate(dateStr string, cal config.Calendar, daysInYear int) (config.Event, error) {
-	// Create empty event for returning errors
-	emptyEvent := config.Event{}
-
 	// Check basic format
-	if len(dateStr) != 12 || dateStr[6] != '-' || dateStr[9] != '-' {
-		return emptyEvent, fmt.Errorf("invalid format, date must be AAYYYY-MM-DD")
+	parts := strictDatePattern.FindStringSubmatch(dateStr)
+	if parts == nil {
+		return config.Event{}, fmt.Errorf("invalid format, date must be AAYYYY-MM-DD")
 	}
+	return exactDate(parts[1], parts[2], parts[3], parts[4], cal, daysInYear)
+}
 
-	// Extract components
-	ageAbbrev := dateStr[:2]
-	yearStr := dateStr[2:6]
-	monthStr := dateStr[7:9]
-	dayStr := dateStr[10:12]
+// exactDate validates the parts of an exact date against a calendar
+func exactDate(ageAbbrev, yearStr, monthStr, dayStr string, cal config.Calendar, daysInYear int) (config.Event, error) {
+	// Create empty event for returning errors
+	emptyEvent := config.Event{}
 
 	// Validate components can be parsed as numbers
 	year, err1 := strconv.Atoi(yearStr)
@@ -61,13 +80,7 @@ func ValidateEventDate(dateStr string, cal config.Calendar, daysInYear int) (con
 	}
 
 	// Calculate days since 0
-	totalDays := year * daysInYear
-
-	// Sum days of months before the selected month
-	for i := 0; i < month-1; i++ {
-		totalDays += cal.Months[i].Days
-	}
-	totalDays += day
+	totalDays := DaysFromDate(cal, daysInYear, year, month, day)
 
 	// Create and return the event
 	event := config.Event{
@@ -83,6 +96,119 @@ func ValidateEventDate(dateStr string, cal config.Calendar, daysInYear int) (con
 	return event, nil
 }
 
+// ParseEventDate parses an event date that may only be known roughly:
+// AAYYYY-MM-DD for an exact day, AAYYYY-MM for sometime in a month, AAYYYY
+// for sometime in a year, or "c. AAYYYY ±N" for around a year give or
+// take N years. Unlike ValidateEventDate it also takes unpadded numbers,
+// e.g. DF100-5-4.
+func ParseEventDate(input string, cal config.Calendar, daysInYear int) (config.Event, error) {
+	input = strings.TrimSpace(input)
+
+	if parts := circaPattern.FindStringSubmatch(input); parts != nil {
+		event, err := ParseEventDate(parts[1], cal, daysInYear)
+		if err != nil {
+			return config.Event{}, err
+		}
+		if event.Precision != config.PrecisionYear {
+			return config.Event{}, fmt.Errorf("circa dates take a year, e.g. c. %s0100 ±10", event.AgeAbbrev)
+		}
+
+		years := 0
+		if parts[2] != "" {
+			years, _ = strconv.Atoi(parts[2])
+		}
+		event.Precision = config.PrecisionCirca
+		event.CircaYears = years
+		event.EarliestDays = DaysFromDate(cal, daysInYear, event.Year-years, 1, 1)
+		event.LatestDays = lastDayOfYear(cal, daysInYear, event.Year+years)
+		return event, nil
+	}
+
+	parts := canonicalDatePattern.FindStringSubmatch(input)
+	if parts == nil {
+		parts = looseDatePattern.FindStringSubmatch(input)
+	}
+	if parts == nil {
+		return config.Event{}, fmt.Errorf("invalid format, date must be AAYYYY-MM-DD, AAYYYY-MM, AAYYYY or c. AAYYYY ±N")
+	}
+	if parts[4] != "" {
+		return exactDate(parts[1], parts[2], parts[3], parts[4], cal, daysInYear)
+	}
+
+	// The month is validated through the first day of the period
+	month := "1"
+	if parts[3] != "" {
+		month = parts[3]
+	}
+	event, err := exactDate(parts[1], parts[2], month, "1", cal, daysInYear)
+	if err != nil {
+		return config.Event{}, err
+	}
+
+	event.Day = 0
+	event.EarliestDays = event.DaysSinceZero
+	if parts[3] != "" {
+		event.Precision = config.PrecisionMonth
+		event.LatestDays = event.DaysSinceZero + cal.Months[event.Month-1].Days - 1
+	} else {
+		event.Precision = config.PrecisionYear
+		event.Month = 0
+		event.LatestDays = lastDayOfYear(cal, daysInYear, event.Year)
+	}
+	return event, nil
+}
+
+// lastDayOfYear returns the days since 0 of a year's final day
+func lastDayOfYear(cal config.Calendar, daysInYear, year int) int {
+	if len(cal.Months) == 0 {
+		return DaysFromDate(cal, daysInYear, year, 1, 1)
+	}
+	last := len(cal.Months)
+	return DaysFromDate(cal, daysInYear, year, last, cal.Months[last-1].Days)
+}
+
+// SetEventEnd applies an optional end to an event. The input may be an end
+// date in AAYYYY-MM-DD format, a duration in days, or blank for a
+// single-day event.
+func SetEventEnd(event *config.Event, input string, cal config.Calendar, daysInYear int) error {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil
+	}
+
+	var end config.Event
+	if duration, err := strconv.Atoi(input); err == nil {
+		if duration < 1 {
+			return fmt.Errorf("duration must be at least 1 day")
+		}
+		end, err = DateFromDays(cal, daysInYear, event.DaysSinceZero+duration-1)
+		if err != nil {
+			return err
+		}
+	} else {
+		end, err = ValidateEventDate(input, cal, daysInYear)
+		if err != nil {
+			return fmt.Errorf("end date: %w", err)
+		}
+	}
+
+	if end.DaysSinceZero < event.DaysSinceZero {
+		return fmt.Errorf("end date must not be before the start date")
+	}
+	if end.DaysSinceZero == event.DaysSinceZero {
+		return nil
+	}
+
+	event.EndAgeAbbrev = end.AgeAbbrev
+	event.EndYear = end.Year
+	event.EndMonth = end.Month
+	event.EndDay = end.Day
+	event.EndDaysSinceZero = end.DaysSinceZero
+	event.DurationDays = event.Length()
+
+	return nil
+}
+
 // ValidateEventName validates an event name
 func ValidateEventName(name string) error {
 	if name == "" {
@@ -91,29 +217,363 @@ func ValidateEventName(name string) error {
 	return nil
 }
 
-// CreateEvent creates a new event from the provided data
-func CreateEvent(cal config.Calendar, event config.Event) error {
-	// Load template
-	tmplPath := filepath.Join("templates", "event.md.tmpl")
-	tmpl, err := template.ParseFiles(tmplPath)
+// EventTemplateData is the context event templates are rendered with. The
+// event's own fields are available directly, e.g. {{.Name}}.
+type EventTemplateData struct {
+	config.Event
+
+	// The event's calendar, e.g. for ranging over .Calendar.Months
+	Calendar config.Calendar
+
+	// Characters of the event's calendar alive on its start date
+	CharactersAlive []CharacterAge
+
+	// Full path of each event location, e.g. "Westmarch > Brindle"
+	LocationPaths []string
+
+	// Initial description, e.g. from an import
+	Description string
+}
+
+// AgeOf returns the age of the named character on the event's start date,
+// or -1 if the character is unknown or not yet born.
+func (d EventTemplateData) AgeOf(name string) int {
+	for _, ch := range d.CharactersAlive {
+		if strings.EqualFold(ch.Name, name) {
+			return ch.Age
+		}
+	}
+	return -1
+}
+
+// newEventTemplateData gathers everything an event template can render
+func newEventTemplateData(cfg config.Config, cal config.Calendar, event config.Event) (EventTemplateData, error) {
+	characters, err := config.LoadCharacters()
 	if err != nil {
-		return fmt.Errorf("failed to load template: %w", err)
+		return EventTemplateData{}, err
 	}
 
-	// Create a file name based on event name and daysSinceZero
-	safeName := strings.ReplaceAll(strings.ToLower(event.Name), " ", "_")
-	outFile := filepath.Join("events", fmt.Sprintf("%s_%d.md", safeName, event.DaysSinceZero))
+	locations, err := config.LoadLocations()
+	if err != nil {
+		return EventTemplateData{}, err
+	}
 
-	f, err := os.Create(outFile)
+	paths := make([]string, len(event.Locations))
+	for i, name := range event.Locations {
+		paths[i] = name
+		if path := LocationPath(locations, name); len(path) > 0 {
+			paths[i] = strings.Join(path, " > ")
+		}
+	}
+
+	return EventTemplateData{
+		Event:           event,
+		Calendar:        cal,
+		CharactersAlive: CharactersAliveOn(characters, cal, cfg.DaysInYear, event.DaysSinceZero),
+		LocationPaths:   paths,
+		Description:     strings.TrimSpace(event.Body),
+	}, nil
+}
+
+// ErrEventExists is returned when a new event's file name is already taken
+var ErrEventExists = errors.New("an event file already exists")
+
+// CreateEvent creates a new event from the provided data. A body set on the
+// event is handed to the template as its description. It never overwrites
+// another event: if the file is taken the returned error wraps
+// ErrEventExists, and CreateEventRenamed or MergeEvent can be used instead.
+func CreateEvent(cfg config.Config, cal config.Calendar, event config.Event) error {
+	event, outFile, err := prepareEvent(cfg, event)
 	if err != nil {
-		return fmt.Errorf("failed to create event file: %w", err)
+		return err
+	}
+
+	if fileExists(outFile) {
+		return fmt.Errorf("%w: %s", ErrEventExists, outFile)
+	}
+
+	return writeNewEvent(cfg, cal, event, outFile)
+}
+
+// CreateEventRenamed creates a new event under the first free numbered
+// variant of its file name, e.g. battle_100_2.md, keeping both events.
+func CreateEventRenamed(cfg config.Config, cal config.Calendar, event config.Event) error {
+	event, outFile, err := prepareEvent(cfg, event)
+	if err != nil {
+		return err
+	}
+
+	ext := filepath.Ext(outFile)
+	base := strings.TrimSuffix(outFile, ext)
+	for n := 2; ; n++ {
+		err := writeNewEvent(cfg, cal, event, outFile)
+		if !errors.Is(err, ErrEventExists) {
+			return err
+		}
+		outFile = fmt.Sprintf("%s_%d%s", base, n, ext)
+	}
+}
+
+// MergeEvent folds a new event into the existing event whose file it would
+// overwrite. The existing event keeps its ID, name, date, template and
+// notes; tags, locations, characters, links, knowledge and template
+// fields are combined, and a category, end date or repeat rule is only
+// taken from the new event when the existing one has none. The event stays
+// GM-only if either version is.
+func MergeEvent(cfg config.Config, event config.Event) error {
+	event, outFile, err := prepareEvent(cfg, event)
+	if err != nil {
+		return err
+	}
+
+	existing, ok, err := readEventFile(outFile)
+	if err != nil {
+		return fmt.Errorf("failed to read event file: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("%s is not an event file and can't be merged into", outFile)
+	}
+
+	existing.Tags = appendMissing(existing.Tags, event.Tags)
+	existing.Locations = appendMissing(existing.Locations, event.Locations)
+	existing.Characters = appendMissing(existing.Characters, event.Characters)
+	if existing.Category == "" {
+		existing.Category = event.Category
+	}
+	if !existing.IsRanged() && event.IsRanged() {
+		existing.EndAgeAbbrev = event.EndAgeAbbrev
+		existing.EndYear = event.EndYear
+		existing.EndMonth = event.EndMonth
+		existing.EndDay = event.EndDay
+		existing.EndDaysSinceZero = event.EndDaysSinceZero
+		existing.DurationDays = event.DurationDays
+	}
+	if existing.Recurrence == nil {
+		existing.Recurrence = event.Recurrence
+	}
+	existing.GMOnly = existing.GMOnly || event.GMOnly
+	for name, value := range event.Fields {
+		if existing.Fields[name] == "" {
+			if existing.Fields == nil {
+				existing.Fields = make(map[string]string)
+			}
+			existing.Fields[name] = value
+		}
+	}
+
+	ids := []string{existing.ID}
+	for _, link := range event.Links {
+		if link.Target == existing.ID || hasLink(existing.Links, link) {
+			continue
+		}
+		existing.Links = append(existing.Links, link)
+		ids = append(ids, link.Target)
+	}
+
+	for _, k := range event.KnownBy {
+		if _, known := KnowledgeOf(existing, k.Character, k.LearnedDays); !known {
+			existing.KnownBy = append(existing.KnownBy, k)
+		}
+	}
+
+	if err := UpdateEvent(existing); err != nil {
+		return err
+	}
+
+	if len(ids) > 1 {
+		events, err := LoadEvents()
+		if err != nil {
+			return err
+		}
+		if err := UpdateLinkSections(events, ids); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// prepareEvent assigns a new event its ID, flags it as scheduled when it
+// lies after the campaign or party clock and works out its file path
+func prepareEvent(cfg config.Config, event config.Event) (config.Event, string, error) {
+	if event.ID == "" {
+		event.ID = NewEventID()
+	}
+
+	// Events after the campaign's current date, or their party's, haven't
+	// happened yet
+	if event.Status == "" {
+		if current, ok := eventClock(cfg, event); ok && event.DaysSinceZero > current.DaysSinceZero {
+			event.Status = config.StatusScheduled
+		}
+	}
+
+	outFile, err := EventFilePath(cfg, event)
+	if err != nil {
+		return event, "", err
+	}
+	return event, outFile, nil
+}
+
+// eventClock returns the current date an event is measured against: its
+// party's when it has one, otherwise its calendar's clock
+func eventClock(cfg config.Config, event config.Event) (config.Event, bool) {
+	if event.Party != "" {
+		parties, err := config.LoadParties()
+		if err != nil {
+			return config.Event{}, false
+		}
+		index := FindParty(parties, event.Party)
+		if index < 0 {
+			return config.Event{}, false
+		}
+		date, err := PartyDate(cfg, parties[index])
+		return date, err == nil
+	}
+
+	cal, err := FindCalendar(cfg, event.CalendarName)
+	if err != nil {
+		return config.Event{}, false
+	}
+	current, ok, err := CurrentDate(cal, cfg.DaysInYear)
+	return current, err == nil && ok
+}
+
+// writeNewEvent renders an event through the template and writes it to
+// outFile in one step, so a failing template never leaves a partial file.
+// It fails with ErrEventExists rather than replace a file at outFile.
+func writeNewEvent(cfg config.Config, cal config.Calendar, event config.Event, outFile string) error {
+	// Load template
+	tmpl, err := parseEventTemplate(event.Template, eventTemplateFuncs(cfg, cal))
+	if err != nil {
+		return err
+	}
+
+	data, err := newEventTemplateData(cfg, cal, event)
+	if err != nil {
+		return err
+	}
+
+	// Write front matter so the event can be read back for lookups
+	var b bytes.Buffer
+	if err := writeFrontMatter(&b, event); err != nil {
+		return fmt.Errorf("failed to write event file: %w", err)
 	}
-	defer f.Close()
 
 	// Execute template
-	if err := tmpl.Execute(f, event); err != nil {
+	if err := tmpl.Execute(&b, data); err != nil {
 		return fmt.Errorf("failed to write event file: %w", err)
 	}
 
+	if err := os.MkdirAll(filepath.Dir(outFile), 0755); err != nil {
+		return fmt.Errorf("failed to create event directory: %w", err)
+	}
+	if err := writeFileExclusive(outFile, b.Bytes()); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("%w: %s", ErrEventExists, outFile)
+		}
+		return fmt.Errorf("failed to create event file: %w", err)
+	}
+
+	// Show the new links on both ends
+	if len(event.Links) > 0 {
+		events, err := LoadEvents()
+		if err != nil {
+			return err
+		}
+
+		ids := []string{event.ID}
+		for _, link := range event.Links {
+			ids = append(ids, link.Target)
+		}
+		if err := UpdateLinkSections(events, ids); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+// fileExists reports whether something already exists at path
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// appendMissing appends the values not already in list, ignoring case
+func appendMissing(list, values []string) []string {
+	for _, v := range values {
+		found := false
+		for _, existing := range list {
+			if strings.EqualFold(existing, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// hasLink reports whether links already contains link
+func hasLink(links []config.EventLink, link config.EventLink) bool {
+	for _, l := range links {
+		if l == link {
+			return true
+		}
+	}
+	return false
+}
+
+// GetEventDetails returns a formatted string with event details and its
+// connections to other events
+func GetEventDetails(event config.Event, conns EventConnections) string {
+	var details strings.Builder
+
+	details.WriteString(fmt.Sprintf("Event: %s (%s)\n", event.Name, event.ID))
+	details.WriteString(fmt.Sprintf("Calendar: %s, Date: %s", event.CalendarName, event.DateDescription()))
+	if event.IsRanged() {
+		details.WriteString(fmt.Sprintf(" to %s (%d days)", event.EndDateString(), event.Length()))
+	}
+	details.WriteString("\n")
+	if event.Status != "" {
+		details.WriteString(fmt.Sprintf("Status: %s\n", event.Status))
+	}
+	if len(event.Locations) > 0 {
+		details.WriteString(fmt.Sprintf("Locations: %s\n", strings.Join(event.Locations, ", ")))
+	}
+	if event.Party != "" {
+		details.WriteString(fmt.Sprintf("Party: %s\n", event.Party))
+	}
+	if len(event.Characters) > 0 {
+		details.WriteString(fmt.Sprintf("Characters: %s\n", strings.Join(event.Characters, ", ")))
+	}
+	names := make([]string, 0, len(event.Fields))
+	for name := range event.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		details.WriteString(fmt.Sprintf("%s: %s\n", name, event.Fields[name]))
+	}
+	for _, k := range event.KnownBy {
+		details.WriteString(fmt.Sprintf("Known by %s since %s\n", k.Character, k.Learned))
+	}
+
+	write := func(heading string, list []Connection) {
+		if len(list) == 0 {
+			return
+		}
+		details.WriteString(fmt.Sprintf("\n%s:\n", heading))
+		for _, conn := range list {
+			details.WriteString(fmt.Sprintf("- %s %s (%s)\n", conn.Label, conn.Event.Name, conn.Event.DateString()))
+		}
+	}
+	write("What it led to", conns.LeadsTo)
+	write("What led to it", conns.LedFrom)
+	write("Related", conns.Related)
+
+	return details.String()
+}
@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sksmith/gmcli/internal/config"
+)
+
+// ExportsDir holds generated exports
+const ExportsDir = "exports"
+
+// ExportTimeline writes the events as a single chronological markdown
+// document and returns its path. Player view exports go to a separate
+// file and leave out GM-only events and sections.
+func ExportTimeline(events []config.Event, filter EventFilter) (string, error) {
+	name := "timeline.md"
+	if filter.PlayerView {
+		name = "timeline-player.md"
+	}
+
+	return writeTimeline(filepath.Join(ExportsDir, name), "Timeline", FilterEvents(events, filter))
+}
+
+// writeTimeline renders events under a title and writes them to path
+func writeTimeline(path, title string, events []config.Event) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, []byte(RenderTimeline(title, events)), 0644); err != nil {
+		return "", fmt.Errorf("failed to write export: %w", err)
+	}
+
+	return path, nil
+}
+
+// RenderTimeline renders events as a markdown timeline. Links are drawn
+// only between the given events, so filtered out events never appear.
+func RenderTimeline(title string, events []config.Event) string {
+	idx := BuildLinkIndex(events)
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# %s\n", title))
+
+	for _, event := range events {
+		b.WriteString(fmt.Sprintf("\n## %s: %s\n\n", event.DateString(), event.Name))
+
+		meta := []string{event.CalendarName}
+		if event.IsRanged() {
+			meta = append(meta, fmt.Sprintf("until %s (%d days)", event.EndDateString(), event.Length()))
+		}
+		if event.Recurrence != nil {
+			meta = append(meta, "repeats "+event.Recurrence.String())
+		}
+		if event.Category != "" {
+			meta = append(meta, event.Category)
+		}
+		if len(event.Locations) > 0 {
+			meta = append(meta, strings.Join(event.Locations, ", "))
+		}
+		for _, tag := range event.Tags {
+			meta = append(meta, "#"+tag)
+		}
+		b.WriteString(fmt.Sprintf("*%s*\n", strings.Join(meta, " · ")))
+
+		if body := exportBody(event); body != "" {
+			b.WriteString("\n" + body + "\n")
+		}
+
+		conns := idx.Connections(event)
+		if !conns.IsEmpty() {
+			b.WriteString("\n")
+		}
+		for _, list := range [][]Connection{conns.LedFrom, conns.LeadsTo, conns.Related} {
+			for _, conn := range list {
+				b.WriteString(fmt.Sprintf("- %s %s (%s)\n", conn.Label, conn.Event.Name, conn.Event.DateString()))
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// exportBody prepares an event body for embedding in an export: the
+// generated links section and the title heading are dropped and the
+// remaining headings are nested one level deeper
+func exportBody(event config.Event) string {
+	body := removeManagedSection(event.Body, linksStartMarker, linksEndMarker)
+
+	var lines []string
+	for i, line := range strings.Split(strings.TrimSpace(body), "\n") {
+		if i == 0 && strings.TrimSpace(line) == "# "+event.Name {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			line = "#" + line
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// removeManagedSection drops the text between two markers, markers included
+func removeManagedSection(content, startMarker, endMarker string) string {
+	start := strings.Index(content, startMarker)
+	end := strings.Index(content, endMarker)
+	if start < 0 || end < start {
+		return content
+	}
+	return content[:start] + content[end+len(endMarker):]
+}
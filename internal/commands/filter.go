@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/sksmith/gmcli/internal/config"
+)
+
+// EventFilter narrows down event listings. Zero values match everything.
+type EventFilter struct {
+	Tags     []string // events must carry every tag
+	Calendar string   // calendar name
+	From, To int      // inclusive range in days since 0
+
+	// PlayerView hides GM-only events and strips GM-only sections from
+	// event bodies
+	PlayerView bool
+}
+
+// IsEmpty reports whether the filter matches every event.
+func (f EventFilter) IsEmpty() bool {
+	return len(f.Tags) == 0 && f.Calendar == "" && f.From == 0 && f.To == 0 && !f.PlayerView
+}
+
+// Match reports whether an event passes the filter. Recurring events
+// match a date range while their rule is active.
+func (f EventFilter) Match(event config.Event) bool {
+	if f.PlayerView && event.GMOnly {
+		return false
+	}
+
+	for _, tag := range f.Tags {
+		if !event.HasTag(tag) {
+			return false
+		}
+	}
+
+	if f.Calendar != "" && event.CalendarName != f.Calendar {
+		return false
+	}
+
+	first, last := event.Window()
+	if event.Recurrence != nil {
+		last = event.Recurrence.Until
+		if last == 0 {
+			last = math.MaxInt
+		}
+	}
+	if f.From != 0 && last < f.From {
+		return false
+	}
+	if f.To != 0 && first > f.To {
+		return false
+	}
+
+	return true
+}
+
+// String returns a short description of the active filter.
+func (f EventFilter) String() string {
+	var parts []string
+	if len(f.Tags) > 0 {
+		parts = append(parts, "tags: "+strings.Join(f.Tags, ", "))
+	}
+	if f.Calendar != "" {
+		parts = append(parts, "calendar: "+f.Calendar)
+	}
+	if f.From != 0 {
+		parts = append(parts, fmt.Sprintf("from day %d", f.From))
+	}
+	if f.To != 0 {
+		parts = append(parts, fmt.Sprintf("to day %d", f.To))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FilterEvents returns the events passing the filter. In player view the
+// returned events have their GM-only sections removed, along with the
+// generated links section, which can name GM-only events.
+func FilterEvents(events []config.Event, filter EventFilter) []config.Event {
+	if filter.IsEmpty() {
+		return events
+	}
+
+	var matches []config.Event
+	for _, event := range events {
+		if !filter.Match(event) {
+			continue
+		}
+		if filter.PlayerView {
+			event.Body = removeManagedSection(StripSecrets(event.Body), linksStartMarker, linksEndMarker)
+		}
+		matches = append(matches, event)
+	}
+	return matches
+}
+
+// ParseTags splits a comma-separated tag list, normalising each tag to
+// lower case and dropping blanks and duplicates.
+func ParseTags(input string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(input, ",") {
+		tag := strings.ToLower(strings.TrimSpace(part))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// NormalizeCategory trims and lower-cases an event category.
+func NormalizeCategory(input string) string {
+	return strings.ToLower(strings.TrimSpace(input))
+}
@@ -0,0 +1,271 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sksmith/gmcli/internal/config"
+)
+
+// DefaultHistoryLimit is how many operations the history keeps when the
+// config doesn't set its own limit
+const DefaultHistoryLimit = 50
+
+// ErrNothingToUndo is returned when every recorded operation is undone
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// ErrNothingToRedo is returned when no undone operation is left to redo
+var ErrNothingToRedo = errors.New("nothing to redo")
+
+// historyLimit returns how many operations the history keeps
+func historyLimit(cfg config.Config) int {
+	if cfg.HistoryLimit > 0 {
+		return cfg.HistoryLimit
+	}
+	return DefaultHistoryLimit
+}
+
+// Operation tracks the workspace files a state-changing operation touches.
+// Begin one before making any change and record it once done.
+type Operation struct {
+	before map[string]string
+}
+
+// BeginOperation snapshots the workspace ahead of an operation.
+func BeginOperation() (*Operation, error) {
+	files, err := snapshotWorkspace()
+	if err != nil {
+		return nil, err
+	}
+	return &Operation{before: files}, nil
+}
+
+// Record adds what changed since the operation began to the history.
+// Nothing is recorded when nothing changed. Recording drops the operations
+// that were undone, since they can no longer be redone, and the oldest
+// beyond the history limit.
+func (op *Operation) Record(cfg config.Config, action, reason string) error {
+	after, err := snapshotWorkspace()
+	if err != nil {
+		return err
+	}
+	changes := diffSnapshots(op.before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	history, err := config.LoadHistory()
+	if err != nil {
+		return err
+	}
+	for len(history) > 0 && history[len(history)-1].Undone {
+		history = history[:len(history)-1]
+	}
+
+	id := 1
+	if len(history) > 0 {
+		id = history[len(history)-1].ID + 1
+	}
+	history = append(history, config.HistoryEntry{
+		ID:      id,
+		Time:    time.Now().Format(time.RFC3339),
+		Action:  action,
+		Reason:  strings.TrimSpace(reason),
+		Changes: changes,
+	})
+	if limit := historyLimit(cfg); len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+	return config.SaveHistory(history)
+}
+
+// Track runs a state-changing operation and records what it changed in the
+// history. Changes are recorded even when the operation fails part way, so
+// they can be undone too.
+func Track(cfg config.Config, action, reason string, run func() error) error {
+	op, err := BeginOperation()
+	if err != nil {
+		return err
+	}
+	runErr := run()
+	if err := op.Record(cfg, action, reason); err != nil && runErr == nil {
+		return fmt.Errorf("failed to record history: %w", err)
+	}
+	return runErr
+}
+
+// snapshotWorkspace reads the state files and event files that exist
+func snapshotWorkspace() (map[string]string, error) {
+	paths := config.StateFiles()
+	err := filepath.WalkDir(config.EventsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(path, ".md") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list event files: %w", err)
+	}
+
+	files := make(map[string]string, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		files[path] = string(data)
+	}
+	return files, nil
+}
+
+// diffSnapshots lists the files created, changed or removed between two
+// snapshots, by path
+func diffSnapshots(before, after map[string]string) []config.FileChange {
+	paths := make(map[string]bool)
+	for path := range before {
+		paths[path] = true
+	}
+	for path := range after {
+		paths[path] = true
+	}
+
+	var changes []config.FileChange
+	for path := range paths {
+		old, hadOld := before[path]
+		cur, hasCur := after[path]
+		if hadOld == hasCur && old == cur {
+			continue
+		}
+
+		change := config.FileChange{Path: path}
+		if hadOld {
+			change.Before = &old
+		}
+		if hasCur {
+			change.After = &cur
+		}
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// Undo reverts the last n operations that haven't been undone, most recent
+// first, and returns them. It stops at an operation whose files were
+// edited since outside the history.
+func Undo(n int) ([]config.HistoryEntry, error) {
+	history, err := config.LoadHistory()
+	if err != nil {
+		return nil, err
+	}
+
+	var undone []config.HistoryEntry
+	for i := len(history) - 1; i >= 0 && len(undone) < n; i-- {
+		if history[i].Undone {
+			continue
+		}
+		if err := applyChanges(history[i].Changes, true); err != nil {
+			return undone, saveAfterError(history, fmt.Errorf("failed to undo '%s': %w", history[i].Action, err))
+		}
+		history[i].Undone = true
+		undone = append(undone, history[i])
+	}
+	if len(undone) == 0 {
+		return nil, ErrNothingToUndo
+	}
+	return undone, config.SaveHistory(history)
+}
+
+// Redo applies again the n earliest undone operations, oldest first, and
+// returns them.
+func Redo(n int) ([]config.HistoryEntry, error) {
+	history, err := config.LoadHistory()
+	if err != nil {
+		return nil, err
+	}
+
+	var redone []config.HistoryEntry
+	for i := range history {
+		if len(redone) == n {
+			break
+		}
+		if !history[i].Undone {
+			continue
+		}
+		if err := applyChanges(history[i].Changes, false); err != nil {
+			return redone, saveAfterError(history, fmt.Errorf("failed to redo '%s': %w", history[i].Action, err))
+		}
+		history[i].Undone = false
+		redone = append(redone, history[i])
+	}
+	if len(redone) == 0 {
+		return nil, ErrNothingToRedo
+	}
+	return redone, config.SaveHistory(history)
+}
+
+// saveAfterError saves the operations undone or redone before an error,
+// returning the error
+func saveAfterError(history []config.HistoryEntry, err error) error {
+	if saveErr := config.SaveHistory(history); saveErr != nil {
+		return fmt.Errorf("%w, and failed to save history: %v", err, saveErr)
+	}
+	return err
+}
+
+// applyChanges puts files back as they were before an operation, or as
+// it left them. Every file is checked first, so nothing is written when
+// one has been edited since.
+func applyChanges(changes []config.FileChange, undo bool) error {
+	for _, change := range changes {
+		expect := change.After
+		if !undo {
+			expect = change.Before
+		}
+
+		data, err := os.ReadFile(change.Path)
+		exists := err == nil
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read %s: %w", change.Path, err)
+		}
+		if exists != (expect != nil) || (exists && string(data) != *expect) {
+			return fmt.Errorf("%s was changed since", change.Path)
+		}
+	}
+
+	for _, change := range changes {
+		want := change.Before
+		if !undo {
+			want = change.After
+		}
+
+		if want == nil {
+			if err := os.Remove(change.Path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove %s: %w", change.Path, err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(change.Path), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", change.Path, err)
+		}
+		if err := os.WriteFile(change.Path, []byte(*want), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", change.Path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sksmith/gmcli/internal/config"
+)
+
+// ImportRow is one event read from an import file.
+type ImportRow struct {
+	Line     int // line the row starts on, for error reports
+	Calendar string
+	Date     string
+	Name     string
+	Tags     string // comma-separated
+	Body     string
+}
+
+// ImportError reports a row that can't be imported.
+type ImportError struct {
+	Line int
+	Err  error
+}
+
+func (e ImportError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// ImportReport summarises an import.
+type ImportReport struct {
+	Events []config.Event // valid events, created unless it was a dry run
+	Errors []ImportError
+	DryRun bool
+}
+
+// ReadImportFile reads event rows from a .csv or .json file. CSV files need
+// a header row naming the calendar, date, name, tags and body columns; JSON
+// files hold an array of objects with those keys, where tags may be a list
+// or a comma-separated string.
+func ReadImportFile(path string) ([]ImportRow, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return readImportCSV(content)
+	case ".json":
+		return readImportJSON(content)
+	}
+	return nil, fmt.Errorf("unsupported import file '%s', expected .csv or .json", path)
+}
+
+// readImportCSV reads rows from CSV content with a header row
+func readImportCSV(content []byte) ([]ImportRow, error) {
+	r := csv.NewReader(bytes.NewReader(content))
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"calendar", "date", "name"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header is missing the '%s' column", required)
+		}
+	}
+
+	var rows []ImportRow
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		line, _ := r.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		rows = append(rows, ImportRow{
+			Line:     line,
+			Calendar: field("calendar"),
+			Date:     field("date"),
+			Name:     field("name"),
+			Tags:     field("tags"),
+			Body:     field("body"),
+		})
+	}
+
+	return rows, nil
+}
+
+// jsonImportRow is the JSON form of an import row
+type jsonImportRow struct {
+	Calendar string          `json:"calendar"`
+	Date     string          `json:"date"`
+	Name     string          `json:"name"`
+	Tags     json.RawMessage `json:"tags"`
+	Body     string          `json:"body"`
+}
+
+// readImportJSON reads rows from a JSON array of objects
+func readImportJSON(content []byte) ([]ImportRow, error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil, fmt.Errorf("JSON import must be an array of events")
+	}
+
+	var rows []ImportRow
+	for dec.More() {
+		line := lineAt(content, int(dec.InputOffset()))
+
+		var raw jsonImportRow
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("failed to read JSON at line %d: %w", line, err)
+		}
+
+		row := ImportRow{
+			Line:     line,
+			Calendar: strings.TrimSpace(raw.Calendar),
+			Date:     strings.TrimSpace(raw.Date),
+			Name:     strings.TrimSpace(raw.Name),
+			Body:     raw.Body,
+		}
+
+		if len(raw.Tags) > 0 {
+			var list []string
+			if err := json.Unmarshal(raw.Tags, &list); err == nil {
+				row.Tags = strings.Join(list, ",")
+			} else if err := json.Unmarshal(raw.Tags, &row.Tags); err != nil {
+				return nil, fmt.Errorf("line %d: tags must be a list or a string", line)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// lineAt returns the line of the first non-space character at or after
+// offset, skipping the comma between array elements
+func lineAt(content []byte, offset int) int {
+	for offset < len(content) && strings.ContainsRune(" \t\r\n,", rune(content[offset])) {
+		offset++
+	}
+	return bytes.Count(content[:offset], []byte("\n")) + 1
+}
+
+// ImportEvents validates every row and, unless dryRun is set, creates the
+// valid ones through the normal template pipeline. Every invalid row is
+// reported; rows that would overwrite an existing event or another row
+// count as invalid, as do rows that fail to be created.
+func ImportEvents(cfg config.Config, rows []ImportRow, dryRun bool) (ImportReport, error) {
+	report := ImportReport{DryRun: dryRun}
+	calendars := make([]config.Calendar, 0, len(rows))
+	lines := make([]int, 0, len(rows))
+	paths := make(map[string]int)
+
+	for _, row := range rows {
+		event, cal, err := importEvent(cfg, row)
+		if err == nil {
+			var path string
+			event.ID = NewEventID()
+			path, err = EventFilePath(cfg, event)
+			switch {
+			case err != nil:
+			case paths[path] != 0:
+				err = fmt.Errorf("same file as the event on line %d", paths[path])
+			case fileExists(path):
+				err = fmt.Errorf("%w: %s", ErrEventExists, path)
+			default:
+				paths[path] = row.Line
+			}
+		}
+
+		if err != nil {
+			report.Errors = append(report.Errors, ImportError{Line: row.Line, Err: err})
+			continue
+		}
+		report.Events = append(report.Events, event)
+		calendars = append(calendars, cal)
+		lines = append(lines, row.Line)
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	// A row failing to be created is reported like a bad row, keeping
+	// the events created before and after it
+	valid := report.Events
+	report.Events = nil
+	for i, event := range valid {
+		if err := CreateEvent(cfg, calendars[i], event); err != nil {
+			report.Errors = append(report.Errors, ImportError{Line: lines[i], Err: fmt.Errorf("failed to create '%s': %w", event.Name, err)})
+			continue
+		}
+		report.Events = append(report.Events, event)
+	}
+	sort.SliceStable(report.Errors, func(i, j int) bool {
+		return report.Errors[i].Line < report.Errors[j].Line
+	})
+
+	return report, nil
+}
+
+// importEvent validates a row with the same rules as events entered by
+// hand, so dates may be approximate or written with unpadded numbers
+func importEvent(cfg config.Config, row ImportRow) (config.Event, config.Calendar, error) {
+	var cal config.Calendar
+	found := false
+	for _, c := range cfg.Calendars {
+		if strings.EqualFold(c.Name, row.Calendar) || strings.EqualFold(c.Abbreviation, row.Calendar) {
+			cal, found = c, true
+			break
+		}
+	}
+	if !found {
+		return config.Event{}, cal, fmt.Errorf("calendar '%s' not found", row.Calendar)
+	}
+
+	event, err := ParseEventDate(row.Date, cal, cfg.DaysInYear)
+	if err != nil {
+		return config.Event{}, cal, err
+	}
+
+	if err := ValidateEventName(row.Name); err != nil {
+		return config.Event{}, cal, err
+	}
+	event.Name = row.Name
+	event.Tags = ParseTags(row.Tags)
+	event.Body = strings.TrimSpace(row.Body)
+
+	return event, cal, nil
+}
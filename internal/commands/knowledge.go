@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/sksmith/gmcli/internal/config"
+)
+
+// FindCharacter returns the character with the given name, ignoring case.
+func FindCharacter(characters []config.Character, name string) (config.Character, bool) {
+	for _, ch := range characters {
+		if strings.EqualFold(ch.Name, name) {
+			return ch, true
+		}
+	}
+	return config.Character{}, false
+}
+
+// ParseCharacters resolves a comma-separated list of character names
+// against the registry, returning their registered spelling.
+func ParseCharacters(input string, characters []config.Character) ([]string, error) {
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		ch, ok := FindCharacter(characters, part)
+		if !ok {
+			return nil, fmt.Errorf("character '%s' not found", part)
+		}
+		if !seen[ch.Name] {
+			seen[ch.Name] = true
+			names = append(names, ch.Name)
+		}
+	}
+
+	return names, nil
+}
+
+// RecordKnowledge notes that a character learned of an event on the given
+// date, replacing any earlier record for that character.
+func RecordKnowledge(cfg config.Config, event config.Event, characterName, learned string) error {
+	characters, err := config.LoadCharacters()
+	if err != nil {
+		return err
+	}
+	ch, ok := FindCharacter(characters, characterName)
+	if !ok {
+		return fmt.Errorf("character '%s' not found", characterName)
+	}
+	if ch.Calendar != event.CalendarName {
+		return fmt.Errorf("%s uses the %s calendar, but the event uses %s", ch.Name, ch.Calendar, event.CalendarName)
+	}
+
+	cal, err := FindCalendar(cfg, event.CalendarName)
+	if err != nil {
+		return err
+	}
+	date, err := ValidateEventDate(learned, cal, cfg.DaysInYear)
+	if err != nil {
+		return err
+	}
+
+	knowledge := config.Knowledge{
+		Character:   ch.Name,
+		Learned:     date.DateString(),
+		LearnedDays: date.DaysSinceZero,
+	}
+
+	// Copy so the caller's event is left untouched
+	event.KnownBy = append([]config.Knowledge(nil), event.KnownBy...)
+
+	replaced := false
+	for i, k := range event.KnownBy {
+		if strings.EqualFold(k.Character, ch.Name) {
+			event.KnownBy[i] = knowledge
+			replaced = true
+		}
+	}
+	if !replaced {
+		event.KnownBy = append(event.KnownBy, knowledge)
+	}
+
+	return UpdateEvent(event)
+}
+
+// KnowledgeOf returns when a character learned of an event, if they have
+// learned of it by the given day.
+func KnowledgeOf(event config.Event, characterName string, asOf int) (config.Knowledge, bool) {
+	for _, k := range event.KnownBy {
+		if strings.EqualFold(k.Character, characterName) && k.LearnedDays <= asOf {
+			return k, true
+		}
+	}
+	return config.Knowledge{}, false
+}
+
+// KnownEvents returns the events a character knows about as of the given
+// day, in chronological order.
+func KnownEvents(events []config.Event, characterName string, asOf int) []config.Event {
+	var known []config.Event
+	for _, event := range events {
+		if _, ok := KnowledgeOf(event, characterName, asOf); ok {
+			known = append(known, event)
+		}
+	}
+	return known
+}
+
+// ExportCharacterKnowledge writes a timeline of the events a character
+// knows about as of a date and returns its path. GM-only sections are
+// always stripped since characters only know what happened.
+func ExportCharacterKnowledge(events []config.Event, ch config.Character, asOf config.Event) (string, error) {
+	known := KnownEvents(events, ch.Name, asOf.DaysSinceZero)
+	for i := range known {
+		known[i].Body = StripSecrets(known[i].Body)
+	}
+
+	path := filepath.Join(ExportsDir, fmt.Sprintf("%s_knowledge.md", Slugify(ch.Name)))
+	title := fmt.Sprintf("What %s Knows as of %s", ch.Name, asOf.DateString())
+
+	return writeTimeline(path, title, known)
+}
@@ -0,0 +1,481 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/sksmith/gmcli/internal/config"
+	"gopkg.in/yaml.v2"
+)
+
+// maxSlugLength keeps generated file names well under filesystem limits
+const maxSlugLength = 80
+
+// transliterations maps accented and special Latin letters to ASCII
+var transliterations = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'ç': "c", 'ć': "c", 'č': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'ı': "i",
+	'ł': "l", 'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'ř': "r", 'ś': "s", 'š': "s", 'ş': "s", 'ť': "t", 'ţ': "t",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u",
+	'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'þ': "th",
+}
+
+// Slugify turns a name into a lowercase ASCII string safe to use in file
+// names. Accented Latin letters are transliterated, any other run of
+// characters becomes a single underscore, and "event" is returned when
+// nothing usable is left.
+func Slugify(name string) string {
+	var b strings.Builder
+	pendingSep := false
+
+	for _, r := range strings.ToLower(name) {
+		var part string
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			part = string(r)
+		case transliterations[r] != "":
+			part = transliterations[r]
+		case r == '\'' || r == '’':
+			// Drop apostrophes so "King's" becomes "kings"
+			continue
+		default:
+			pendingSep = true
+			continue
+		}
+
+		if pendingSep && b.Len() > 0 {
+			b.WriteByte('_')
+		}
+		pendingSep = false
+		b.WriteString(part)
+	}
+
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "_")
+	}
+	if slug == "" {
+		return "event"
+	}
+	return slug
+}
+
+// EventPathPattern returns the configured event file layout
+func EventPathPattern(cfg config.Config) string {
+	if cfg.EventPath == "" {
+		return config.DefaultEventPath
+	}
+	return cfg.EventPath
+}
+
+// ValidateEventPath checks that a layout pattern produces markdown files
+// inside the events directory.
+func ValidateEventPath(pattern string) error {
+	if !strings.HasSuffix(pattern, ".md") {
+		return fmt.Errorf("event path '%s' must end in .md", pattern)
+	}
+	if !strings.Contains(pattern, "{slug}") && !strings.Contains(pattern, "{id}") {
+		return fmt.Errorf("event path '%s' must contain {slug} or {id}", pattern)
+	}
+	if filepath.IsAbs(pattern) {
+		return fmt.Errorf("event path '%s' must be relative", pattern)
+	}
+
+	clean := filepath.Clean(filepath.FromSlash(pattern))
+	if !strings.HasPrefix(clean, config.EventsDir+string(filepath.Separator)) {
+		return fmt.Errorf("event path '%s' must be inside the %s directory", pattern, config.EventsDir)
+	}
+	for _, part := range strings.Split(filepath.ToSlash(clean), "/") {
+		if part == ".." {
+			return fmt.Errorf("event path '%s' must not contain '..'", pattern)
+		}
+	}
+
+	return nil
+}
+
+// EventFilePath returns where an event's file belongs under the configured
+// layout. Supported placeholders are {calendar}, {age}, {year}, {month},
+// {day}, {slug}, {days} and {id}; every value is slugified so names can
+// never escape the events directory.
+func EventFilePath(cfg config.Config, event config.Event) (string, error) {
+	pattern := EventPathPattern(cfg)
+	if err := ValidateEventPath(pattern); err != nil {
+		return "", err
+	}
+
+	id := event.ID
+	if id == "" {
+		id = "event"
+	}
+
+	replacer := strings.NewReplacer(
+		"{calendar}", Slugify(event.CalendarName),
+		"{age}", Slugify(event.AgeAbbrev),
+		"{year}", fmt.Sprintf("%04d", event.Year),
+		"{month}", fmt.Sprintf("%02d", event.Month),
+		"{day}", fmt.Sprintf("%02d", event.Day),
+		"{slug}", Slugify(event.Name),
+		"{days}", strconv.Itoa(event.DaysSinceZero),
+		"{id}", Slugify(id),
+	)
+
+	path := replacer.Replace(pattern)
+	if strings.ContainsAny(path, "{}") {
+		return "", fmt.Errorf("event path '%s' has an unknown placeholder", pattern)
+	}
+
+	return filepath.Clean(filepath.FromSlash(path)), nil
+}
+
+// FileMove is an event file that needs to move to match the layout.
+type FileMove struct {
+	Event config.Event
+	From  string
+	To    string
+}
+
+// PlanMigration lists the event files that are not where the configured
+// layout puts them. Events that would land on the same file are kept
+// apart with numbered file names, as CreateEventRenamed does.
+func PlanMigration(cfg config.Config, events []config.Event) ([]FileMove, error) {
+	desired := make([]string, len(events))
+	taken := make(map[string]bool)
+	eventPaths := make(map[string]bool)
+
+	for i, event := range events {
+		to, err := EventFilePath(cfg, event)
+		if err != nil {
+			return nil, err
+		}
+		desired[i] = to
+		eventPaths[filepath.Clean(event.Path)] = true
+	}
+
+	// Files already in place, including numbered variants, stay put
+	staying := make([]bool, len(events))
+	for i, event := range events {
+		from := filepath.Clean(event.Path)
+		if !taken[from] && isPathVariant(from, desired[i]) {
+			staying[i] = true
+			taken[from] = true
+		}
+	}
+
+	var moves []FileMove
+	for i, event := range events {
+		if staying[i] {
+			continue
+		}
+
+		// Other events' files are moved out of the way first, but files
+		// that aren't events must never be overwritten
+		to := desired[i]
+		ext := filepath.Ext(to)
+		base := strings.TrimSuffix(to, ext)
+		for n := 2; taken[to] || (fileExists(to) && !eventPaths[to]); n++ {
+			to = fmt.Sprintf("%s_%d%s", base, n, ext)
+		}
+		taken[to] = true
+
+		moves = append(moves, FileMove{Event: event, From: filepath.Clean(event.Path), To: to})
+	}
+
+	return moves, nil
+}
+
+// isPathVariant reports whether path is target or a numbered variant of
+// it such as battle_100_2.md
+func isPathVariant(path, target string) bool {
+	if path == target {
+		return true
+	}
+
+	ext := filepath.Ext(target)
+	prefix := strings.TrimSuffix(target, ext) + "_"
+	if !strings.HasPrefix(path, prefix) || !strings.HasSuffix(path, ext) {
+		return false
+	}
+
+	n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(path, prefix), ext))
+	return err == nil && n >= 2
+}
+
+// MigrationReport describes what a migration did.
+type MigrationReport struct {
+	Moved    int      // files moved into the layout
+	Upgraded int      // files given front matter
+	Skipped  []string // files that couldn't be read as events, with why
+}
+
+// legacyFileName matches the events/<name>_<days>.md files written before
+// events carried front matter
+var legacyFileName = regexp.MustCompile(`^(.+)_(\d+)\.md$`)
+
+// legacyLine matches a "- Key: value" line of a legacy event's details
+var legacyLine = regexp.MustCompile(`^- ([A-Za-z0-9 ]+): (.+)$`)
+
+// FindLegacyEventFiles lists the event files without front matter.
+func FindLegacyEventFiles() ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(config.EventsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if _, _, ok := splitFrontMatter(content); !ok {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list event files: %w", err)
+	}
+	return paths, nil
+}
+
+// parseLegacyEvent rebuilds the metadata of an event file without front
+// matter. The name comes from its title, or else its file name, and the
+// date from its details, or else the days in its file name.
+func parseLegacyEvent(cfg config.Config, path string, content []byte) (config.Event, error) {
+	name, days := "", -1
+	if parts := legacyFileName.FindStringSubmatch(filepath.Base(path)); parts != nil {
+		name = strings.ReplaceAll(parts[1], "_", " ")
+		days, _ = strconv.Atoi(parts[2])
+	}
+
+	details := make(map[string]string)
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if i == 0 && strings.HasPrefix(line, "# ") {
+			name = strings.TrimSpace(line[2:])
+			continue
+		}
+		if parts := legacyLine.FindStringSubmatch(line); parts != nil {
+			if _, seen := details[parts[1]]; !seen {
+				details[parts[1]] = strings.TrimSpace(parts[2])
+			}
+		}
+	}
+	if name == "" {
+		return config.Event{}, fmt.Errorf("no name in its title or file name")
+	}
+
+	// "- Calendar: Name (AB)"
+	calName := strings.TrimSpace(details["Calendar"])
+	if i := strings.LastIndex(calName, " ("); i >= 0 {
+		calName = calName[:i]
+	}
+	if calName == "" && len(cfg.Calendars) == 1 {
+		calName = cfg.Calendars[0].Name
+	}
+	cal, err := FindCalendar(cfg, calName)
+	if err != nil {
+		return config.Event{}, err
+	}
+
+	event, err := ParseEventDate(details["Date"], cal, cfg.DaysInYear)
+	if err != nil || event.IsApproximate() {
+		if days < 0 {
+			return config.Event{}, fmt.Errorf("no date in its details or file name")
+		}
+		event, err = DateFromDays(cal, cfg.DaysInYear, days)
+		if err != nil {
+			return config.Event{}, err
+		}
+	}
+
+	event.ID = NewEventID()
+	event.Name = name
+	event.CalendarName = cal.Name
+	event.CalendarAbbrev = cal.Abbreviation
+	return event, nil
+}
+
+// upgradeLegacyEvents writes front matter into the event files that have
+// none, returning how many were upgraded and the files it couldn't parse
+func upgradeLegacyEvents(cfg config.Config) (int, []string, error) {
+	paths, err := FindLegacyEventFiles()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	upgraded := 0
+	var skipped []string
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return upgraded, skipped, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		event, err := parseLegacyEvent(cfg, path, content)
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+
+		var b bytes.Buffer
+		if err := writeFrontMatter(&b, event); err != nil {
+			return upgraded, skipped, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		b.Write(content)
+		if err := writeFileAtomic(path, b.Bytes()); err != nil {
+			return upgraded, skipped, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		upgraded++
+	}
+	return upgraded, skipped, nil
+}
+
+// pinEventIDs writes into their front matter the IDs of events that fall
+// back to their file name, so links keep pointing at them once the files
+// move. Events whose file names clash get fresh IDs.
+func pinEventIDs(events []config.Event) error {
+	stored := make(map[string]bool)
+	var unpinned []int
+	for i, event := range events {
+		content, err := os.ReadFile(event.Path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", event.Path, err)
+		}
+		meta, _, _ := splitFrontMatter(content)
+		var fields struct {
+			ID string `yaml:"id"`
+		}
+		if err := yaml.Unmarshal(meta, &fields); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", event.Path, err)
+		}
+		if fields.ID != "" {
+			stored[fields.ID] = true
+		} else {
+			unpinned = append(unpinned, i)
+		}
+	}
+
+	for _, i := range unpinned {
+		if stored[events[i].ID] {
+			events[i].ID = NewEventID()
+		}
+		stored[events[i].ID] = true
+		if err := UpdateEvent(events[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MigrateEventFiles moves every event file into the configured layout,
+// refreshes the generated links sections and removes directories left
+// empty. Files written before events carried front matter are given it
+// first, and files it can't make sense of are reported and left alone.
+func MigrateEventFiles(cfg config.Config) (MigrationReport, error) {
+	var report MigrationReport
+	var err error
+	report.Upgraded, report.Skipped, err = upgradeLegacyEvents(cfg)
+	if err != nil {
+		return report, err
+	}
+
+	events, err := LoadEvents()
+	if err != nil {
+		return report, err
+	}
+	if err := pinEventIDs(events); err != nil {
+		return report, err
+	}
+
+	moves, err := PlanMigration(cfg, events)
+	if err != nil {
+		return report, err
+	}
+	if len(moves) == 0 {
+		return report, nil
+	}
+
+	// Move through temporary names first so files can swap places
+	for i := range moves {
+		tmp := fmt.Sprintf("%s.migrating-%d", moves[i].From, i)
+		if err := os.Rename(moves[i].From, tmp); err != nil {
+			return report, fmt.Errorf("failed to move %s: %w", moves[i].From, err)
+		}
+		moves[i].From = tmp
+	}
+	for _, move := range moves {
+		if err := os.MkdirAll(filepath.Dir(move.To), 0755); err != nil {
+			return report, fmt.Errorf("failed to create directory for %s: %w", move.To, err)
+		}
+		if err := os.Rename(move.From, move.To); err != nil {
+			return report, fmt.Errorf("failed to move %s: %w", move.From, err)
+		}
+		report.Moved++
+	}
+
+	if err := removeEmptyDirs(config.EventsDir); err != nil {
+		return report, err
+	}
+
+	// Relative links between event files changed with the layout
+	events, err = LoadEvents()
+	if err != nil {
+		return report, err
+	}
+	var ids []string
+	for _, event := range events {
+		if len(event.Links) > 0 || strings.Contains(event.Body, linksStartMarker) {
+			ids = append(ids, event.ID)
+		}
+	}
+	if err := UpdateLinkSections(events, ids); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+// removeEmptyDirs deletes empty directories below root, deepest first
+func removeEmptyDirs(root string) error {
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != root {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clean up %s: %w", root, err)
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		entries, err := os.ReadDir(dirs[i])
+		if err != nil {
+			return fmt.Errorf("failed to clean up %s: %w", dirs[i], err)
+		}
+		if len(entries) == 0 {
+			if err := os.Remove(dirs[i]); err != nil {
+				return fmt.Errorf("failed to clean up %s: %w", dirs[i], err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,280 @@
+package commands
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sksmith/gmcli/internal/config"
+)
+
+// Relations between events, read from the event carrying the link to its
+// target: "caused" means the event caused the target, "followed" that it
+// came after the target
+const (
+	RelationCaused         = "caused"
+	RelationFollowed       = "followed"
+	RelationConcurrentWith = "concurrent-with"
+	RelationContradicts    = "contradicts"
+)
+
+// Relations lists every supported relation
+var Relations = []string{RelationCaused, RelationFollowed, RelationConcurrentWith, RelationContradicts}
+
+// Markers delimiting the generated links section of an event file
+const (
+	linksStartMarker = "<!-- links:start -->"
+	linksEndMarker   = "<!-- links:end -->"
+)
+
+// Connection is a related event as seen from another event.
+type Connection struct {
+	Label string // e.g. "caused", "caused by"
+	Event config.Event
+}
+
+// EventConnections groups an event's relations by direction.
+type EventConnections struct {
+	LeadsTo []Connection // events this one led to
+	LedFrom []Connection // events that led to this one
+	Related []Connection // concurrent or contradicting events
+}
+
+// IsEmpty reports whether the event has no connections.
+func (c EventConnections) IsEmpty() bool {
+	return len(c.LeadsTo) == 0 && len(c.LedFrom) == 0 && len(c.Related) == 0
+}
+
+// backlink is an incoming link recorded in the index
+type backlink struct {
+	source   string
+	relation string
+}
+
+// LinkIndex resolves event IDs and records incoming links.
+type LinkIndex struct {
+	byID      map[string]config.Event
+	backlinks map[string][]backlink
+}
+
+// NewEventID returns a new random event ID.
+func NewEventID() string {
+	b := make([]byte, 4)
+	// crypto/rand.Read never returns an error, it crashes the program instead
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// BuildLinkIndex indexes events by ID along with every backlink.
+func BuildLinkIndex(events []config.Event) *LinkIndex {
+	idx := &LinkIndex{
+		byID:      make(map[string]config.Event),
+		backlinks: make(map[string][]backlink),
+	}
+
+	for _, event := range events {
+		idx.byID[event.ID] = event
+		for _, link := range event.Links {
+			idx.backlinks[link.Target] = append(idx.backlinks[link.Target], backlink{
+				source:   event.ID,
+				relation: link.Relation,
+			})
+		}
+	}
+
+	return idx
+}
+
+// Event returns the event with the given ID.
+func (idx *LinkIndex) Event(id string) (config.Event, bool) {
+	event, ok := idx.byID[id]
+	return event, ok
+}
+
+// Connections returns everything an event leads to, was led to by, or is
+// otherwise related to, from both its own links and its backlinks. Links
+// to unknown events are ignored.
+func (idx *LinkIndex) Connections(event config.Event) EventConnections {
+	var conns EventConnections
+
+	for _, link := range event.Links {
+		target, ok := idx.byID[link.Target]
+		if !ok {
+			continue
+		}
+		switch link.Relation {
+		case RelationCaused:
+			conns.LeadsTo = append(conns.LeadsTo, Connection{Label: "caused", Event: target})
+		case RelationFollowed:
+			conns.LedFrom = append(conns.LedFrom, Connection{Label: "followed", Event: target})
+		case RelationConcurrentWith:
+			conns.Related = append(conns.Related, Connection{Label: "concurrent with", Event: target})
+		case RelationContradicts:
+			conns.Related = append(conns.Related, Connection{Label: "contradicts", Event: target})
+		}
+	}
+
+	for _, back := range idx.backlinks[event.ID] {
+		source, ok := idx.byID[back.source]
+		if !ok {
+			continue
+		}
+		switch back.relation {
+		case RelationCaused:
+			conns.LedFrom = append(conns.LedFrom, Connection{Label: "caused by", Event: source})
+		case RelationFollowed:
+			conns.LeadsTo = append(conns.LeadsTo, Connection{Label: "followed by", Event: source})
+		case RelationConcurrentWith:
+			conns.Related = append(conns.Related, Connection{Label: "concurrent with", Event: source})
+		case RelationContradicts:
+			conns.Related = append(conns.Related, Connection{Label: "contradicted by", Event: source})
+		}
+	}
+
+	return conns
+}
+
+// ParseLinks parses links written as "relation:target" pairs separated by
+// semicolons, e.g. "followed:The Sundering; concurrent-with:1a2b3c4d". Targets are
+// matched by ID, then by name ignoring case.
+func ParseLinks(input string, events []config.Event) ([]config.EventLink, error) {
+	var links []config.EventLink
+
+	for _, part := range strings.Split(input, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		relation, target, found := strings.Cut(part, ":")
+		if !found {
+			return nil, fmt.Errorf("link '%s' must be written as relation:event", part)
+		}
+		relation = strings.ToLower(strings.TrimSpace(relation))
+		target = strings.TrimSpace(target)
+
+		if !isRelation(relation) {
+			return nil, fmt.Errorf("unknown relation '%s', expected one of %s", relation, strings.Join(Relations, ", "))
+		}
+
+		event, err := FindEvent(events, target)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, config.EventLink{Relation: relation, Target: event.ID})
+	}
+
+	return links, nil
+}
+
+// isRelation reports whether a relation is supported
+func isRelation(relation string) bool {
+	for _, r := range Relations {
+		if r == relation {
+			return true
+		}
+	}
+	return false
+}
+
+// FindEvent returns the event with the given ID, or failing that the only
+// event with the given name.
+func FindEvent(events []config.Event, ref string) (config.Event, error) {
+	for _, event := range events {
+		if event.ID == ref {
+			return event, nil
+		}
+	}
+
+	var matches []config.Event
+	for _, event := range events {
+		if strings.EqualFold(event.Name, ref) {
+			matches = append(matches, event)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return config.Event{}, fmt.Errorf("event '%s' not found", ref)
+	case 1:
+		return matches[0], nil
+	}
+	return config.Event{}, fmt.Errorf("several events are named '%s', use an ID instead", ref)
+}
+
+// UpdateLinkSections rewrites the generated links section of the given
+// events' files to match the current links and backlinks.
+func UpdateLinkSections(events []config.Event, ids []string) error {
+	idx := BuildLinkIndex(events)
+
+	for _, id := range ids {
+		event, ok := idx.Event(id)
+		if !ok || event.Path == "" {
+			continue
+		}
+
+		content, err := os.ReadFile(event.Path)
+		if err != nil {
+			return fmt.Errorf("failed to update links of %s: %w", event.Path, err)
+		}
+
+		section := renderLinksSection(event, idx.Connections(event))
+		updated := replaceManagedSection(string(content), linksStartMarker, linksEndMarker, section)
+
+		if err := writeFileAtomic(event.Path, []byte(updated)); err != nil {
+			return fmt.Errorf("failed to update links of %s: %w", event.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// renderLinksSection renders the markdown listing an event's connections
+func renderLinksSection(event config.Event, conns EventConnections) string {
+	if conns.IsEmpty() {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## Links\n")
+
+	write := func(heading string, list []Connection) {
+		if len(list) == 0 {
+			return
+		}
+		b.WriteString(fmt.Sprintf("\n### %s\n", heading))
+		for _, conn := range list {
+			target := conn.Event.Name
+			if rel, err := filepath.Rel(filepath.Dir(event.Path), conn.Event.Path); err == nil && conn.Event.Path != "" {
+				target = fmt.Sprintf("[%s](%s)", conn.Event.Name, filepath.ToSlash(rel))
+			}
+			b.WriteString(fmt.Sprintf("- %s %s (%s)\n", conn.Label, target, conn.Event.DateString()))
+		}
+	}
+
+	write("What It Led To", conns.LeadsTo)
+	write("What Led To It", conns.LedFrom)
+	write("Related", conns.Related)
+
+	return b.String()
+}
+
+// replaceManagedSection swaps the text between two markers for section,
+// appending the markers at the end of the content if they are missing
+func replaceManagedSection(content, startMarker, endMarker, section string) string {
+	block := startMarker + "\n" + section + endMarker
+
+	start := strings.Index(content, startMarker)
+	end := strings.Index(content, endMarker)
+	if start >= 0 && end > start {
+		return content[:start] + block + content[end+len(endMarker):]
+	}
+
+	if section == "" {
+		return content
+	}
+	return strings.TrimRight(content, "\n") + "\n\n" + block + "\n"
+}
@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sksmith/gmcli/internal/config"
+)
+
+// LocationKinds lists the suggested levels of the location hierarchy,
+// from largest to smallest
+var LocationKinds = []string{"continent", "region", "city", "building"}
+
+// FindLocation returns the location with the given name, ignoring case.
+func FindLocation(locations []config.Location, name string) (config.Location, bool) {
+	for _, loc := range locations {
+		if strings.EqualFold(loc.Name, name) {
+			return loc, true
+		}
+	}
+	return config.Location{}, false
+}
+
+// ValidateLocation checks a new location against the registry
+func ValidateLocation(loc config.Location, locations []config.Location) error {
+	if strings.TrimSpace(loc.Name) == "" {
+		return fmt.Errorf("location name cannot be empty")
+	}
+	if strings.Contains(loc.Name, ",") {
+		return fmt.Errorf("location name cannot contain commas")
+	}
+	if _, exists := FindLocation(locations, loc.Name); exists {
+		return fmt.Errorf("location '%s' already exists", loc.Name)
+	}
+	if loc.Parent != "" {
+		if _, exists := FindLocation(locations, loc.Parent); !exists {
+			return fmt.Errorf("parent location '%s' not found", loc.Parent)
+		}
+	}
+	return nil
+}
+
+// CreateLocation validates a location and adds it to the registry. The
+// parent name is normalised to the registered spelling.
+func CreateLocation(loc config.Location) error {
+	locations, err := config.LoadLocations()
+	if err != nil {
+		return err
+	}
+
+	if err := ValidateLocation(loc, locations); err != nil {
+		return err
+	}
+	if parent, ok := FindLocation(locations, loc.Parent); ok {
+		loc.Parent = parent.Name
+	}
+	loc.Kind = strings.ToLower(strings.TrimSpace(loc.Kind))
+
+	return config.SaveLocations(append(locations, loc))
+}
+
+// ParseLocations resolves a comma-separated list of location names against
+// the registry, returning their registered spelling.
+func ParseLocations(input string, locations []config.Location) ([]string, error) {
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		loc, ok := FindLocation(locations, part)
+		if !ok {
+			return nil, fmt.Errorf("location '%s' not found", part)
+		}
+		if !seen[loc.Name] {
+			seen[loc.Name] = true
+			names = append(names, loc.Name)
+		}
+	}
+
+	return names, nil
+}
+
+// LocationPath returns the chain of location names from the outermost
+// place down to the named one.
+func LocationPath(locations []config.Location, name string) []string {
+	var path []string
+	seen := make(map[string]bool)
+
+	for name != "" && !seen[name] {
+		seen[name] = true
+		loc, ok := FindLocation(locations, name)
+		if !ok {
+			break
+		}
+		path = append([]string{loc.Name}, path...)
+		name = loc.Parent
+	}
+
+	return path
+}
+
+// IsWithin reports whether a location is the given place or nested
+// anywhere inside it.
+func IsWithin(locations []config.Location, name, place string) bool {
+	for _, ancestor := range LocationPath(locations, name) {
+		if strings.EqualFold(ancestor, place) {
+			return true
+		}
+	}
+	return false
+}
+
+// EventsAtLocation returns the events held at a place or anywhere inside
+// it, in chronological order.
+func EventsAtLocation(events []config.Event, locations []config.Location, place string) []config.Event {
+	var matches []config.Event
+	for _, event := range events {
+		for _, name := range event.Locations {
+			if IsWithin(locations, name, place) {
+				matches = append(matches, event)
+				break
+			}
+		}
+	}
+
+	SortEvents(matches)
+	return matches
+}
+
+// LocationTree orders locations depth first, each followed by the places
+// inside it, and returns the nesting depth of each.
+func LocationTree(locations []config.Location) ([]config.Location, []int) {
+	var ordered []config.Location
+	var depths []int
+	visited := make(map[string]bool)
+
+	var walk func(parent string, depth int)
+	walk = func(parent string, depth int) {
+		for _, loc := range locations {
+			if visited[loc.Name] || !strings.EqualFold(loc.Parent, parent) {
+				continue
+			}
+			visited[loc.Name] = true
+			ordered = append(ordered, loc)
+			depths = append(depths, depth)
+			walk(loc.Name, depth+1)
+		}
+	}
+	walk("", 0)
+
+	// Locations whose parent is missing are listed at the top level
+	for _, loc := range locations {
+		if !visited[loc.Name] {
+			visited[loc.Name] = true
+			ordered = append(ordered, loc)
+			depths = append(depths, 0)
+			walk(loc.Name, 1)
+		}
+	}
+
+	return ordered, depths
+}
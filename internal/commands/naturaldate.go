@@ -0,0 +1,209 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sksmith/gmcli/internal/config"
+)
+
+// Patterns for relative and natural date expressions
+var (
+	// "+3d", "-2 weeks"
+	relativePattern = regexp.MustCompile(`^([+-])\s*(\d+)\s*([a-z]+)$`)
+	// "3 days after The Sundering"
+	offsetPattern = regexp.MustCompile(`^(\d+)\s*([a-z]+)\s+(after|before)\s+(.+)$`)
+	// "next Forge Fire 12", "last Open Tome 3rd"
+	nextPattern = regexp.MustCompile(`^(next|last)\s+(.+?)\s+(\d{1,2})(?:st|nd|rd|th)?$`)
+	// "the 5th of Open Tome 14240", "5 Open Tome DF14240"
+	namedPattern = regexp.MustCompile(`^(?:the\s+)?(\d{1,2})(?:st|nd|rd|th)?\s+(?:of\s+)?(.+?),?\s+([a-z]*)(\d+)$`)
+)
+
+// CampaignToday returns the current campaign date of a calendar: its clock
+// if set, otherwise the day of its latest exactly dated event. ok is false
+// when there is neither.
+func CampaignToday(cal config.Calendar, daysInYear int, events []config.Event) (int, bool) {
+	if current, ok, err := CurrentDate(cal, daysInYear); err == nil && ok {
+		return current.DaysSinceZero, true
+	}
+
+	today, ok := 0, false
+	for _, event := range events {
+		if event.CalendarName != cal.Name || event.IsApproximate() {
+			continue
+		}
+		if !ok || event.DaysSinceZero > today {
+			today, ok = event.DaysSinceZero, true
+		}
+	}
+	return today, ok
+}
+
+// ResolveDate parses a date that may be written relative to the campaign's
+// current date or to another event, falling back to ParseEventDate.
+// Supported forms are "today", "tomorrow", "yesterday", "+3d", "-2 weeks",
+// "next Forge Fire 12", "last Forge Fire 12", "3 days after <event>" and
+// "the 5th of Open Tome 14240". today is only used by the relative forms;
+// hasToday is false when the campaign has no current date yet.
+func ResolveDate(input string, cal config.Calendar, daysInYear int, today int, hasToday bool, events []config.Event) (config.Event, error) {
+	text := strings.ToLower(strings.Join(strings.Fields(input), " "))
+
+	relativeTo := func() (int, error) {
+		if !hasToday {
+			return 0, fmt.Errorf("there is no current campaign date yet, enter a full date")
+		}
+		return today, nil
+	}
+
+	switch text {
+	case "today", "tomorrow", "yesterday":
+		day, err := relativeTo()
+		if err != nil {
+			return config.Event{}, err
+		}
+		switch text {
+		case "tomorrow":
+			day++
+		case "yesterday":
+			day--
+		}
+		return DateFromDays(cal, daysInYear, day)
+	}
+
+	if parts := relativePattern.FindStringSubmatch(text); parts != nil {
+		day, err := relativeTo()
+		if err != nil {
+			return config.Event{}, err
+		}
+		n, _ := strconv.Atoi(parts[2])
+		if parts[1] == "-" {
+			n = -n
+		}
+		return addPeriod(cal, daysInYear, day, n, parts[3])
+	}
+
+	if parts := offsetPattern.FindStringSubmatch(text); parts != nil {
+		// Look the event up in the original spelling
+		ref := strings.TrimSpace(input[strings.Index(strings.ToLower(input), parts[3])+len(parts[3]):])
+		event, err := FindEvent(events, ref)
+		if err != nil {
+			return config.Event{}, err
+		}
+		if event.CalendarName != cal.Name {
+			return config.Event{}, fmt.Errorf("event '%s' is in the %s calendar", event.Name, event.CalendarName)
+		}
+		if event.IsApproximate() {
+			return config.Event{}, fmt.Errorf("event '%s' has no exact date to count from", event.Name)
+		}
+
+		n, _ := strconv.Atoi(parts[1])
+		start := event.DaysSinceZero
+		if parts[3] == "after" {
+			// Count from the end of multi-day events
+			start = event.LastDay()
+		} else {
+			n = -n
+		}
+		return addPeriod(cal, daysInYear, start, n, parts[2])
+	}
+
+	if parts := nextPattern.FindStringSubmatch(text); parts != nil {
+		day, err := relativeTo()
+		if err != nil {
+			return config.Event{}, err
+		}
+		month, err := findMonth(cal, parts[2])
+		if err != nil {
+			return config.Event{}, err
+		}
+		dayOfMonth, _ := strconv.Atoi(parts[3])
+		return nextMonthDay(cal, daysInYear, day, month, dayOfMonth, parts[1] == "next")
+	}
+
+	if parts := namedPattern.FindStringSubmatch(text); parts != nil {
+		month, err := findMonth(cal, parts[2])
+		if err == nil {
+			year, _ := strconv.Atoi(parts[4])
+			age := strings.ToUpper(parts[3])
+			if age == "" {
+				age = AgeForYear(cal, year).Abbreviation
+			}
+			// Years here can run past the four digits ValidateEventDate takes
+			return exactDate(age, parts[4], strconv.Itoa(month), parts[1], cal, daysInYear)
+		}
+	}
+
+	return ParseEventDate(input, cal, daysInYear)
+}
+
+// findMonth returns the 1-based number of the month with the given name
+func findMonth(cal config.Calendar, name string) (int, error) {
+	for i, month := range cal.Months {
+		if strings.EqualFold(month.Name, strings.TrimSpace(name)) {
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("month '%s' not found in calendar '%s'", name, cal.Name)
+}
+
+// addPeriod moves a day by n days, weeks, months or years. Weeks follow
+// the calendar's weekdays when it has them, and moving by months or years
+// keeps the day of the month where it can.
+func addPeriod(cal config.Calendar, daysInYear int, day, n int, unit string) (config.Event, error) {
+	switch unit {
+	case "d", "day", "days":
+		return DateFromDays(cal, daysInYear, day+n)
+	case "w", "week", "weeks":
+		week := 7
+		if len(cal.Weekdays) > 0 {
+			week = len(cal.Weekdays)
+		}
+		return DateFromDays(cal, daysInYear, day+n*week)
+	case "m", "month", "months", "y", "year", "years":
+		date, err := DateFromDays(cal, daysInYear, day)
+		if err != nil {
+			return config.Event{}, err
+		}
+
+		months := n
+		if strings.HasPrefix(unit, "y") {
+			months = n * len(cal.Months)
+		}
+		index := date.Year*len(cal.Months) + date.Month - 1 + months
+		year, month := index/len(cal.Months), mod(index, len(cal.Months))+1
+		if index < 0 {
+			year = (index - len(cal.Months) + 1) / len(cal.Months)
+		}
+
+		dayOfMonth := date.Day
+		if dayOfMonth > cal.Months[month-1].Days {
+			dayOfMonth = cal.Months[month-1].Days
+		}
+		return DateFromDays(cal, daysInYear, DaysFromDate(cal, daysInYear, year, month, dayOfMonth))
+	}
+	return config.Event{}, fmt.Errorf("unknown unit '%s', expected days, weeks, months or years", unit)
+}
+
+// nextMonthDay finds the first given month and day after (or, for last,
+// before) a day
+func nextMonthDay(cal config.Calendar, daysInYear int, day, month, dayOfMonth int, forward bool) (config.Event, error) {
+	if dayOfMonth < 1 || dayOfMonth > cal.Months[month-1].Days {
+		return config.Event{}, fmt.Errorf("day must be between 1 and %d for month '%s'", cal.Months[month-1].Days, cal.Months[month-1].Name)
+	}
+
+	date, err := DateFromDays(cal, daysInYear, day)
+	if err != nil {
+		return config.Event{}, err
+	}
+
+	candidate := DaysFromDate(cal, daysInYear, date.Year, month, dayOfMonth)
+	switch {
+	case forward && candidate <= day:
+		candidate = DaysFromDate(cal, daysInYear, date.Year+1, month, dayOfMonth)
+	case !forward && candidate >= day:
+		candidate = DaysFromDate(cal, daysInYear, date.Year-1, month, dayOfMonth)
+	}
+	return DateFromDays(cal, daysInYear, candidate)
+}
@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sksmith/gmcli/internal/config"
+)
+
+// FindParty returns the index of the party with the given name, ignoring
+// case, or -1.
+func FindParty(parties []config.Party, name string) int {
+	for i, p := range parties {
+		if strings.EqualFold(p.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// CreateParty validates a party and adds it to the parties. A blank
+// current date starts the party at its calendar's clock.
+func CreateParty(cfg config.Config, party config.Party) error {
+	parties, err := config.LoadParties()
+	if err != nil {
+		return err
+	}
+
+	party.Name = strings.TrimSpace(party.Name)
+	if party.Name == "" {
+		return fmt.Errorf("party name cannot be empty")
+	}
+	if FindParty(parties, party.Name) >= 0 {
+		return fmt.Errorf("party '%s' already exists", party.Name)
+	}
+
+	cal, err := FindCalendar(cfg, party.Calendar)
+	if err != nil {
+		return err
+	}
+	if party.CurrentDate == "" {
+		party.CurrentDate = cal.CurrentDate
+	}
+	if party.CurrentDate == "" {
+		return fmt.Errorf("give the party a date, %s has no current date", cal.Name)
+	}
+	date, err := ValidateEventDate(party.CurrentDate, cal, cfg.DaysInYear)
+	if err != nil {
+		return err
+	}
+	party.CurrentDate = date.DateString()
+
+	return config.SaveParties(append(parties, party))
+}
+
+// PartyDate returns a party's current date.
+func PartyDate(cfg config.Config, party config.Party) (config.Event, error) {
+	cal, err := FindCalendar(cfg, party.Calendar)
+	if err != nil {
+		return config.Event{}, err
+	}
+	date, err := ValidateEventDate(party.CurrentDate, cal, cfg.DaysInYear)
+	if err != nil {
+		return config.Event{}, fmt.Errorf("current date of %s: %w", party.Name, err)
+	}
+	return date, nil
+}
+
+// AdvanceParty moves a party's current date like AdvanceClock does for a
+// calendar, saving the parties. Relative dates count from the party's date.
+func AdvanceParty(cfg config.Config, name, input string, events []config.Event) (config.Event, config.Event, error) {
+	parties, err := config.LoadParties()
+	if err != nil {
+		return config.Event{}, config.Event{}, err
+	}
+	index := FindParty(parties, name)
+	if index < 0 {
+		return config.Event{}, config.Event{}, fmt.Errorf("party '%s' not found", name)
+	}
+	cal, err := FindCalendar(cfg, parties[index].Calendar)
+	if err != nil {
+		return config.Event{}, config.Event{}, err
+	}
+
+	previous, date, err := advanceDate(cal, cfg.DaysInYear, parties[index].CurrentDate, input, events)
+	if err != nil {
+		return config.Event{}, config.Event{}, err
+	}
+
+	parties[index].CurrentDate = date.DateString()
+	if err := config.SaveParties(parties); err != nil {
+		return config.Event{}, config.Event{}, err
+	}
+	return previous, date, nil
+}
+
+// PartyEvents returns the events attributed to a party, or with a blank
+// party the events attributed to none.
+func PartyEvents(events []config.Event, party string) []config.Event {
+	var matched []config.Event
+	for _, event := range events {
+		if strings.EqualFold(event.Party, party) {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+// CatchUp lists what happened between two parties' dates, from the point
+// of view of the party behind: occurrences after its date up to the other
+// party's, of events attributed to the party ahead or to no party. It
+// returns the parties ordered behind first.
+func CatchUp(cfg config.Config, a, b config.Party, events []config.Event) (config.Party, config.Party, []config.Event, error) {
+	if a.Calendar != b.Calendar {
+		return a, b, nil, fmt.Errorf("%s and %s use different calendars", a.Name, b.Name)
+	}
+	cal, err := FindCalendar(cfg, a.Calendar)
+	if err != nil {
+		return a, b, nil, err
+	}
+
+	dateA, err := PartyDate(cfg, a)
+	if err != nil {
+		return a, b, nil, err
+	}
+	dateB, err := PartyDate(cfg, b)
+	if err != nil {
+		return a, b, nil, err
+	}
+
+	behind, ahead := a, b
+	from, to := dateA.DaysSinceZero, dateB.DaysSinceZero
+	if from > to {
+		behind, ahead = b, a
+		from, to = to, from
+	}
+	if from == to {
+		return behind, ahead, nil, nil
+	}
+
+	seen := append(PartyEvents(events, ""), PartyEvents(events, ahead.Name)...)
+	var between []config.Event
+	for _, occ := range OccurrencesBetween(seen, cal, cfg.DaysInYear, from+1, to) {
+		if occ.DaysSinceZero > from {
+			between = append(between, occ)
+		}
+	}
+	return behind, ahead, between, nil
+}
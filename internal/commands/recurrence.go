@@ -0,0 +1,363 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sksmith/gmcli/internal/config"
+)
+
+// Recurrence frequencies
+const (
+	FrequencyDaily    = "daily"
+	FrequencyMonthly  = "monthly"
+	FrequencyAnnually = "annually"
+)
+
+// weekdayOrdinals are the words accepted for the Nth weekday of a month
+var weekdayOrdinals = map[string]int{
+	"1st": 1, "first": 1,
+	"2nd": 2, "second": 2,
+	"3rd": 3, "third": 3,
+	"4th": 4, "fourth": 4,
+	"5th": 5, "fifth": 5,
+	"last": -1,
+}
+
+// ParseRecurrence parses a repeat rule for an event. Supported forms are
+// "daily", "every N days", "monthly [on day X]", "every N months [on day X]",
+// "monthly on the 2nd Moonday" for calendars with weekdays,
+// "annually [on MM-DD]" and "every N years [on MM-DD]", each optionally
+// followed by "until AAYYYY-MM-DD". Blank input means the event does not
+// repeat.
+func ParseRecurrence(input string, event config.Event, cal config.Calendar, daysInYear int) (*config.Recurrence, error) {
+	raw := strings.TrimSpace(input)
+	if raw == "" {
+		return nil, nil
+	}
+	words := strings.Fields(raw)
+
+	rule := &config.Recurrence{Interval: 1}
+
+	// Split off an optional end date first, keeping its case for the age
+	for i := 1; i < len(words); i++ {
+		if !strings.EqualFold(words[i], "until") {
+			continue
+		}
+		until, err := ValidateEventDate(strings.Join(words[i+1:], " "), cal, daysInYear)
+		if err != nil {
+			return nil, fmt.Errorf("until: %w", err)
+		}
+		if until.DaysSinceZero < event.DaysSinceZero {
+			return nil, fmt.Errorf("until date must not be before the event date")
+		}
+		rule.Until = until.DaysSinceZero
+		words = words[:i]
+		break
+	}
+
+	input = strings.ToLower(strings.Join(words, " "))
+	words = strings.Fields(input)
+	var unit string
+
+	switch {
+	case words[0] == "daily":
+		unit, words = "day", words[1:]
+	case words[0] == "monthly":
+		unit, words = "month", words[1:]
+	case words[0] == "annually" || words[0] == "yearly":
+		unit, words = "year", words[1:]
+	case words[0] == "every" && len(words) >= 2:
+		words = words[1:]
+		if n, err := strconv.Atoi(words[0]); err == nil {
+			if n < 1 {
+				return nil, fmt.Errorf("interval must be at least 1")
+			}
+			rule.Interval = n
+			words = words[1:]
+		}
+		if len(words) == 0 {
+			return nil, fmt.Errorf("missing unit, expected days, months or years")
+		}
+		unit = strings.TrimSuffix(words[0], "s")
+		words = words[1:]
+	default:
+		return nil, fmt.Errorf("unrecognised repeat rule '%s'", input)
+	}
+
+	// Optional "on ..." qualifier
+	if len(words) > 0 && words[0] == "on" {
+		words = words[1:]
+	}
+	if len(words) > 0 && words[0] == "day" {
+		words = words[1:]
+	}
+	qualifier := strings.Join(words, " ")
+
+	switch unit {
+	case "day":
+		if qualifier != "" {
+			return nil, fmt.Errorf("daily rules do not take a day qualifier")
+		}
+		rule.Frequency = FrequencyDaily
+
+	case "month":
+		rule.Frequency = FrequencyMonthly
+		rule.Day = event.Day
+		if ordinal, weekday, ok := parseWeekdayQualifier(qualifier); ok {
+			if len(cal.Weekdays) == 0 {
+				return nil, fmt.Errorf("calendar '%s' has no weekdays", cal.Name)
+			}
+			name, found := "", false
+			for _, wd := range cal.Weekdays {
+				if strings.EqualFold(wd, weekday) {
+					name, found = wd, true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unknown weekday '%s', expected one of %s", weekday, strings.Join(cal.Weekdays, ", "))
+			}
+			rule.Day = 0
+			rule.Ordinal, rule.Weekday = ordinal, name
+			break
+		}
+		if qualifier != "" {
+			day, err := strconv.Atoi(qualifier)
+			if err != nil {
+				return nil, fmt.Errorf("monthly rules take a day number, e.g. 'monthly on day 1'")
+			}
+			rule.Day = day
+		}
+		if rule.Day < 1 || rule.Day > longestMonth(cal) {
+			return nil, fmt.Errorf("day must be between 1 and %d", longestMonth(cal))
+		}
+
+	case "year":
+		rule.Frequency = FrequencyAnnually
+		rule.Month, rule.Day = event.Month, event.Day
+		if qualifier != "" {
+			parts := strings.Split(qualifier, "-")
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("annual rules take a MM-DD date, e.g. 'annually on 03-15'")
+			}
+			month, err1 := strconv.Atoi(parts[0])
+			day, err2 := strconv.Atoi(parts[1])
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("annual rules take a MM-DD date, e.g. 'annually on 03-15'")
+			}
+			rule.Month, rule.Day = month, day
+		}
+		if rule.Month < 1 || rule.Month > len(cal.Months) {
+			return nil, fmt.Errorf("month must be between 1 and %d", len(cal.Months))
+		}
+		if m := cal.Months[rule.Month-1]; rule.Day < 1 || rule.Day > m.Days {
+			return nil, fmt.Errorf("day must be between 1 and %d for month '%s'", m.Days, m.Name)
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown unit '%s', expected days, months or years", unit)
+	}
+
+	return rule, nil
+}
+
+// parseWeekdayQualifier splits a qualifier such as "the 2nd moonday" or
+// "last moonday" into its ordinal and weekday
+func parseWeekdayQualifier(qualifier string) (int, string, bool) {
+	words := strings.Fields(qualifier)
+	if len(words) > 0 && words[0] == "the" {
+		words = words[1:]
+	}
+	if len(words) != 2 {
+		return 0, "", false
+	}
+	ordinal, ok := weekdayOrdinals[words[0]]
+	if !ok {
+		return 0, "", false
+	}
+	return ordinal, words[1], true
+}
+
+// nthWeekday returns the day of a month the rule's weekday falls on for
+// the Nth time, or false when the month doesn't have that many
+func nthWeekday(cal config.Calendar, daysInYear, year, month int, rule *config.Recurrence) (int, bool) {
+	first := DaysFromDate(cal, daysInYear, year, month, 1)
+	var matches []int
+	for day := 0; day < cal.Months[month-1].Days; day++ {
+		if Weekday(cal, first+day) == rule.Weekday {
+			matches = append(matches, first+day)
+		}
+	}
+	switch {
+	case rule.Ordinal == -1 && len(matches) > 0:
+		return matches[len(matches)-1], true
+	case rule.Ordinal >= 1 && rule.Ordinal <= len(matches):
+		return matches[rule.Ordinal-1], true
+	}
+	return 0, false
+}
+
+// longestMonth returns the number of days in the calendar's longest month
+func longestMonth(cal config.Calendar) int {
+	longest := 0
+	for _, month := range cal.Months {
+		if month.Days > longest {
+			longest = month.Days
+		}
+	}
+	return longest
+}
+
+// ExpandOccurrences returns every occurrence of an event overlapping the
+// range [from, to] (inclusive, in days since 0). Events without a
+// recurrence yield at most themselves. Monthly and annual rules falling on
+// a day a month does not have are moved to that month's last day, while
+// months without the Nth weekday a rule asks for are skipped.
+func ExpandOccurrences(event config.Event, cal config.Calendar, daysInYear int, from, to int) []config.Event {
+	// An approximate date only certainly falls between from and to when
+	// its whole window does
+	if event.IsApproximate() {
+		first, last := event.Window()
+		if first >= from && last <= to {
+			return []config.Event{event}
+		}
+		return nil
+	}
+
+	var occurrences []config.Event
+	span := event.Length() - 1
+
+	add := func(start int) {
+		if start+span < from || start > to {
+			return
+		}
+		if occurrence, err := shiftEvent(event, cal, daysInYear, start); err == nil {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+
+	rule := event.Recurrence
+	if rule == nil {
+		add(event.DaysSinceZero)
+		return occurrences
+	}
+
+	last := to
+	if rule.Until > 0 && rule.Until < last {
+		last = rule.Until
+	}
+	interval := rule.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	switch rule.Frequency {
+	case FrequencyDaily:
+		start := event.DaysSinceZero
+		// Skip ahead to the first occurrence that can overlap the range
+		if start+span < from {
+			steps := (from - span - start + interval - 1) / interval
+			start += steps * interval
+		}
+		for ; start <= last; start += interval {
+			add(start)
+		}
+
+	case FrequencyMonthly:
+		months := len(cal.Months)
+		if months == 0 {
+			break
+		}
+		for index := event.Year*months + event.Month - 1; ; index += interval {
+			year, month := index/months, index%months+1
+			if rule.Weekday != "" {
+				start, ok := nthWeekday(cal, daysInYear, year, month, rule)
+				if DaysFromDate(cal, daysInYear, year, month, 1) > last {
+					break
+				}
+				if ok && start <= last && start >= event.DaysSinceZero {
+					add(start)
+				}
+				continue
+			}
+			day := min(rule.Day, cal.Months[month-1].Days)
+			start := DaysFromDate(cal, daysInYear, year, month, day)
+			if start > last {
+				break
+			}
+			if start >= event.DaysSinceZero {
+				add(start)
+			}
+		}
+
+	case FrequencyAnnually:
+		if rule.Month < 1 || rule.Month > len(cal.Months) {
+			break
+		}
+		day := min(rule.Day, cal.Months[rule.Month-1].Days)
+		for year := event.Year; ; year += interval {
+			start := DaysFromDate(cal, daysInYear, year, rule.Month, day)
+			if start > last {
+				break
+			}
+			if start >= event.DaysSinceZero {
+				add(start)
+			}
+		}
+	}
+
+	return occurrences
+}
+
+// OccurrencesBetween expands the events of a calendar into every
+// occurrence overlapping the range [from, to], sorted chronologically.
+func OccurrencesBetween(events []config.Event, cal config.Calendar, daysInYear int, from, to int) []config.Event {
+	var occurrences []config.Event
+	for _, event := range events {
+		if event.CalendarName != cal.Name {
+			continue
+		}
+		occurrences = append(occurrences, ExpandOccurrences(event, cal, daysInYear, from, to)...)
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].DaysSinceZero < occurrences[j].DaysSinceZero
+	})
+	return occurrences
+}
+
+// shiftEvent returns a copy of the event moved to start on the given day,
+// keeping its length.
+func shiftEvent(event config.Event, cal config.Calendar, daysInYear int, start int) (config.Event, error) {
+	if start == event.DaysSinceZero {
+		return event, nil
+	}
+
+	date, err := DateFromDays(cal, daysInYear, start)
+	if err != nil {
+		return event, err
+	}
+
+	shifted := event
+	shifted.AgeAbbrev = date.AgeAbbrev
+	shifted.Year = date.Year
+	shifted.Month = date.Month
+	shifted.Day = date.Day
+	shifted.DaysSinceZero = date.DaysSinceZero
+
+	if event.IsRanged() {
+		end, err := DateFromDays(cal, daysInYear, start+event.Length()-1)
+		if err != nil {
+			return event, err
+		}
+		shifted.EndAgeAbbrev = end.AgeAbbrev
+		shifted.EndYear = end.Year
+		shifted.EndMonth = end.Month
+		shifted.EndDay = end.Day
+		shifted.EndDaysSinceZero = end.DaysSinceZero
+	}
+
+	return shifted, nil
+}
@@ -0,0 +1,303 @@
+package commands
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/sksmith/gmcli/internal/config"
+)
+
+// Weight of a match in an event name relative to one in its body
+const nameMatchWeight = 3
+
+// Number of characters of context kept either side of a snippet match
+const snippetContext = 60
+
+// SearchQuery is a parsed search: free-text terms plus constraints.
+type SearchQuery struct {
+	Terms  []string
+	Filter EventFilter
+}
+
+// SearchResult is one ranked search match.
+type SearchResult struct {
+	Event config.Event
+	Score float64
+}
+
+// posting records how often a term appears in one event
+type posting struct {
+	doc       int
+	nameCount int
+	bodyCount int
+}
+
+// SearchIndex is an inverted index over event names and bodies.
+type SearchIndex struct {
+	events   []config.Event
+	postings map[string][]posting
+	terms    []string // sorted, for prefix lookups
+}
+
+// BuildSearchIndex indexes the names and bodies of the given events.
+func BuildSearchIndex(events []config.Event) *SearchIndex {
+	idx := &SearchIndex{
+		events:   events,
+		postings: make(map[string][]posting),
+	}
+
+	for doc, event := range events {
+		counts := make(map[string]*posting)
+		count := func(text string, name bool) {
+			for _, term := range tokenize(text) {
+				p, ok := counts[term]
+				if !ok {
+					p = &posting{doc: doc}
+					counts[term] = p
+				}
+				if name {
+					p.nameCount++
+				} else {
+					p.bodyCount++
+				}
+			}
+		}
+		count(event.Name, true)
+		count(event.Body, false)
+
+		for term, p := range counts {
+			idx.postings[term] = append(idx.postings[term], *p)
+		}
+	}
+
+	for term := range idx.postings {
+		idx.terms = append(idx.terms, term)
+	}
+	sort.Strings(idx.terms)
+
+	return idx
+}
+
+// Search returns the events matching every query term and the query's
+// constraints, best matches first. Terms match words they prefix, so
+// "sunder" finds "Sundering". A query without terms lists every event
+// passing the constraints chronologically.
+func (idx *SearchIndex) Search(query SearchQuery) []SearchResult {
+	var results []SearchResult
+
+	if len(query.Terms) == 0 {
+		for _, event := range idx.events {
+			if query.Filter.Match(event) {
+				results = append(results, SearchResult{Event: event})
+			}
+		}
+		return results
+	}
+
+	scores := make(map[int]float64)
+	for i, term := range query.Terms {
+		termScores := make(map[int]float64)
+		for _, match := range idx.prefixed(term) {
+			postings := idx.postings[match]
+			idf := math.Log(1 + float64(len(idx.events))/float64(len(postings)))
+			for _, p := range postings {
+				termScores[p.doc] += idf * float64(nameMatchWeight*p.nameCount+p.bodyCount)
+			}
+		}
+
+		// Every term must match, so intersect with the previous terms
+		if i == 0 {
+			scores = termScores
+			continue
+		}
+		for doc := range scores {
+			if score, ok := termScores[doc]; ok {
+				scores[doc] += score
+			} else {
+				delete(scores, doc)
+			}
+		}
+	}
+
+	for doc, score := range scores {
+		event := idx.events[doc]
+		if !query.Filter.Match(event) {
+			continue
+		}
+		results = append(results, SearchResult{Event: event, Score: score})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Event.DaysSinceZero < results[j].Event.DaysSinceZero
+	})
+
+	return results
+}
+
+// prefixed returns the indexed terms starting with the given prefix
+func (idx *SearchIndex) prefixed(prefix string) []string {
+	var matches []string
+	start := sort.SearchStrings(idx.terms, prefix)
+	for i := start; i < len(idx.terms) && strings.HasPrefix(idx.terms[i], prefix); i++ {
+		matches = append(matches, idx.terms[i])
+	}
+	return matches
+}
+
+// tokenize splits text into lower-case words
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// ParseSearchQuery parses a search query. Plain words are search terms;
+// constraints are written as calendar:<name or abbreviation>, tag:<tag>,
+// from:<AAYYYY-MM-DD> and to:<AAYYYY-MM-DD>. Date constraints need a
+// calendar, which may be omitted when only one is configured.
+func ParseSearchQuery(input string, cfg config.Config) (SearchQuery, error) {
+	var query SearchQuery
+	var fromStr, toStr string
+	var cal *config.Calendar
+
+	for _, word := range strings.Fields(input) {
+		field, value, found := strings.Cut(word, ":")
+		if !found || value == "" {
+			query.Terms = append(query.Terms, tokenize(word)...)
+			continue
+		}
+
+		switch strings.ToLower(field) {
+		case "calendar":
+			for i := range cfg.Calendars {
+				c := &cfg.Calendars[i]
+				if strings.EqualFold(c.Name, value) || strings.EqualFold(c.Abbreviation, value) {
+					cal = c
+				}
+			}
+			if cal == nil {
+				return query, fmt.Errorf("calendar '%s' not found", value)
+			}
+			query.Filter.Calendar = cal.Name
+		case "tag":
+			query.Filter.Tags = append(query.Filter.Tags, ParseTags(value)...)
+		case "from":
+			fromStr = value
+		case "to":
+			toStr = value
+		default:
+			query.Terms = append(query.Terms, tokenize(word)...)
+		}
+	}
+
+	if fromStr == "" && toStr == "" {
+		return query, nil
+	}
+
+	if cal == nil {
+		if len(cfg.Calendars) != 1 {
+			return query, fmt.Errorf("from: and to: need a calendar: constraint")
+		}
+		cal = &cfg.Calendars[0]
+	}
+
+	if fromStr != "" {
+		from, err := ValidateEventDate(fromStr, *cal, cfg.DaysInYear)
+		if err != nil {
+			return query, fmt.Errorf("from: %w", err)
+		}
+		query.Filter.From = from.DaysSinceZero
+	}
+	if toStr != "" {
+		to, err := ValidateEventDate(toStr, *cal, cfg.DaysInYear)
+		if err != nil {
+			return query, fmt.Errorf("to: %w", err)
+		}
+		query.Filter.To = to.DaysSinceZero
+	}
+	if query.Filter.From != 0 && query.Filter.To != 0 && query.Filter.To < query.Filter.From {
+		return query, fmt.Errorf("to: date must not be before from: date")
+	}
+
+	return query, nil
+}
+
+// Snippet returns a short excerpt of the text around the first match of
+// any term, passing every matching word through highlight.
+func Snippet(text string, terms []string, highlight func(string) string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return ""
+	}
+
+	// Find the first matching word to centre the excerpt on
+	center := -1
+	forEachWord(runes, func(start, end int) {
+		if center < 0 && matchesAnyTerm(string(runes[start:end]), terms) {
+			center = start
+		}
+	})
+
+	from, to := 0, min(len(runes), 2*snippetContext)
+	if center >= 0 {
+		from = max(0, center-snippetContext)
+		to = min(len(runes), center+snippetContext)
+	}
+	window := runes[from:to]
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	last := 0
+	forEachWord(window, func(start, end int) {
+		word := string(window[start:end])
+		if !matchesAnyTerm(word, terms) {
+			return
+		}
+		b.WriteString(string(window[last:start]))
+		b.WriteString(highlight(word))
+		last = end
+	})
+	b.WriteString(string(window[last:]))
+	if to < len(runes) {
+		b.WriteString("…")
+	}
+
+	return b.String()
+}
+
+// forEachWord calls fn with the bounds of every word in runes
+func forEachWord(runes []rune, fn func(start, end int)) {
+	start := -1
+	for i, r := range runes {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWord && start < 0 {
+			start = i
+		} else if !isWord && start >= 0 {
+			fn(start, i)
+			start = -1
+		}
+	}
+	if start >= 0 {
+		fn(start, len(runes))
+	}
+}
+
+// matchesAnyTerm reports whether a word starts with any search term
+func matchesAnyTerm(word string, terms []string) bool {
+	word = strings.ToLower(word)
+	for _, term := range terms {
+		if strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+	return false
+}
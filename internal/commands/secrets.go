@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"strings"
+)
+
+// Fence lines marking a GM-only section of an event body:
+//
+//	:::gm
+//	The duke is secretly a vampire.
+//	:::
+const (
+	secretFenceStart = ":::gm"
+	secretFenceEnd   = ":::"
+)
+
+// StripSecrets removes every GM-only fenced section from an event body. An
+// unterminated section runs to the end of the body.
+func StripSecrets(body string) string {
+	var b strings.Builder
+	inSecret := false
+
+	for _, line := range strings.SplitAfter(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case !inSecret && strings.EqualFold(trimmed, secretFenceStart):
+			inSecret = true
+		case inSecret && trimmed == secretFenceEnd:
+			inSecret = false
+		case !inSecret:
+			b.WriteString(line)
+		}
+	}
+
+	return b.String()
+}
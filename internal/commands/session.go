@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sksmith/gmcli/internal/config"
+)
+
+// SessionTemplateName is the template session-recap events are rendered
+// with, when the workspace has it
+const SessionTemplateName = "session"
+
+// playedLayout is the format of real-world session dates
+const playedLayout = "2006-01-02"
+
+// NextSessionNumber returns the number following the highest one logged.
+func NextSessionNumber(sessions []config.Session) int {
+	next := 1
+	for _, s := range sessions {
+		if s.Number >= next {
+			next = s.Number + 1
+		}
+	}
+	return next
+}
+
+// LastSession returns the latest logged session of a calendar.
+func LastSession(sessions []config.Session, calendar string) (config.Session, bool) {
+	var last config.Session
+	found := false
+	for _, s := range sessions {
+		if s.Calendar == calendar && (!found || s.Number > last.Number) {
+			last = s
+			found = true
+		}
+	}
+	return last, found
+}
+
+// LogSession validates a session, creates its session-recap event spanning
+// the in-game days it covered and adds it to the session log. Start and End
+// only need to be set; their day counts are worked out here.
+func LogSession(cfg config.Config, session config.Session) (config.Session, error) {
+	sessions, err := config.LoadSessions()
+	if err != nil {
+		return session, err
+	}
+
+	if session.Number < 1 {
+		return session, fmt.Errorf("session number must be positive")
+	}
+	for _, s := range sessions {
+		if s.Number == session.Number {
+			return session, fmt.Errorf("session %d is already logged", session.Number)
+		}
+	}
+	if _, err := time.Parse(playedLayout, session.Played); err != nil {
+		return session, fmt.Errorf("played date must be YYYY-MM-DD")
+	}
+
+	cal, err := FindCalendar(cfg, session.Calendar)
+	if err != nil {
+		return session, err
+	}
+	start, err := ValidateEventDate(session.Start, cal, cfg.DaysInYear)
+	if err != nil {
+		return session, fmt.Errorf("invalid start date: %w", err)
+	}
+	end, err := ValidateEventDate(session.End, cal, cfg.DaysInYear)
+	if err != nil {
+		return session, fmt.Errorf("invalid end date: %w", err)
+	}
+	if end.DaysSinceZero < start.DaysSinceZero {
+		return session, fmt.Errorf("session cannot end before it starts")
+	}
+	session.Start, session.StartDays = start.DateString(), start.DaysSinceZero
+	session.End, session.EndDays = end.DateString(), end.DaysSinceZero
+
+	recap := start
+	recap.ID = NewEventID()
+	recap.Name = fmt.Sprintf("Session %d", session.Number)
+	recap.Category = "session"
+	recap.Fields = map[string]string{
+		"session_number": strconv.Itoa(session.Number),
+		"played_on":      session.Played,
+		"players":        session.Players,
+	}
+	if err := SetEventEnd(&recap, session.End, cal, cfg.DaysInYear); err != nil {
+		return session, err
+	}
+	if fileExists(filepath.Join(config.TemplatesDir, SessionTemplateName+templateSuffix)) {
+		recap.Template = SessionTemplateName
+	}
+
+	err = CreateEvent(cfg, cal, recap)
+	if errors.Is(err, ErrEventExists) {
+		err = CreateEventRenamed(cfg, cal, recap)
+	}
+	if err != nil {
+		return session, fmt.Errorf("failed to create session recap: %w", err)
+	}
+	session.Event = recap.ID
+
+	sessions = append(sessions, session)
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Number < sessions[j].Number
+	})
+	if err := config.SaveSessions(sessions); err != nil {
+		return session, err
+	}
+	return session, nil
+}
+
+// TimelineEntry is one line of the unified timeline: either an in-world
+// event or a session, shown through its recap event.
+type TimelineEntry struct {
+	Event   config.Event
+	Session *config.Session // set for sessions
+}
+
+// UnifiedTimeline merges the sessions of a calendar into its events in
+// chronological order. Each session takes the place of its recap event;
+// sessions whose recap is missing are shown from their logged dates.
+func UnifiedTimeline(cal config.Calendar, daysInYear int, sessions []config.Session, events []config.Event) []TimelineEntry {
+	recaps := make(map[string]config.Event)
+	for _, event := range events {
+		recaps[event.ID] = event
+	}
+
+	var entries []TimelineEntry
+	isRecap := make(map[string]bool)
+	for _, s := range sessions {
+		if s.Calendar != cal.Name {
+			continue
+		}
+		s := s
+		event, ok := recaps[s.Event]
+		if ok {
+			isRecap[s.Event] = true
+		} else {
+			event, _ = DateFromDays(cal, daysInYear, s.StartDays)
+			event.Name = fmt.Sprintf("Session %d", s.Number)
+			SetEventEnd(&event, s.End, cal, daysInYear)
+		}
+		entries = append(entries, TimelineEntry{Event: event, Session: &s})
+	}
+
+	for _, event := range events {
+		if event.CalendarName == cal.Name && !isRecap[event.ID] {
+			entries = append(entries, TimelineEntry{Event: event})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Event.DaysSinceZero < entries[j].Event.DaysSinceZero
+	})
+	return entries
+}
@@ -0,0 +1,236 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sksmith/gmcli/internal/config"
+	"gopkg.in/yaml.v2"
+)
+
+const frontMatterDelimiter = "---\n"
+
+// writeFrontMatter writes the event metadata as a YAML front matter block
+func writeFrontMatter(w io.Writer, event config.Event) error {
+	data, err := yaml.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, frontMatterDelimiter); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, frontMatterDelimiter)
+	return err
+}
+
+// splitFrontMatter separates the front matter block from the markdown body.
+// ok is false when the content has no front matter.
+func splitFrontMatter(content []byte) (meta []byte, body []byte, ok bool) {
+	if !bytes.HasPrefix(content, []byte(frontMatterDelimiter)) {
+		return nil, content, false
+	}
+
+	rest := content[len(frontMatterDelimiter):]
+	end := bytes.Index(rest, []byte("\n"+frontMatterDelimiter))
+	if end < 0 {
+		return nil, content, false
+	}
+
+	return rest[:end+1], rest[end+1+len(frontMatterDelimiter):], true
+}
+
+// readEventFile parses an event file, keeping its markdown body. ok is
+// false for files written before events carried front matter.
+func readEventFile(path string) (event config.Event, ok bool, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return event, false, err
+	}
+
+	meta, body, ok := splitFrontMatter(content)
+	if !ok {
+		return event, false, nil
+	}
+
+	if err := yaml.Unmarshal(meta, &event); err != nil {
+		return event, false, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	event.Path = path
+	event.Body = string(body)
+
+	// Events written before IDs existed fall back to their file name
+	if event.ID == "" {
+		event.ID = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	return event, true, nil
+}
+
+// UpdateEvent rewrites the front matter of a loaded event's file with the
+// event's current fields, keeping the markdown body as it is on disk.
+func UpdateEvent(event config.Event) error {
+	if event.Path == "" {
+		return fmt.Errorf("event '%s' has no file", event.Name)
+	}
+
+	content, err := os.ReadFile(event.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read event file: %w", err)
+	}
+	_, body, _ := splitFrontMatter(content)
+
+	var b bytes.Buffer
+	if err := writeFrontMatter(&b, event); err != nil {
+		return fmt.Errorf("failed to write event file: %w", err)
+	}
+	b.Write(body)
+
+	if err := writeFileAtomic(event.Path, b.Bytes()); err != nil {
+		return fmt.Errorf("failed to write event file: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it into place, so readers never see a partially written file
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+
+	// Clean up the temporary file unless the rename succeeded
+	renamed := false
+	defer func() {
+		if !renamed {
+			os.Remove(tmp)
+		}
+	}()
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp, 0644); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	renamed = true
+	return nil
+}
+
+// writeFileExclusive writes data to a new file at path like
+// writeFileAtomic does, but fails with an error wrapping fs.ErrExist
+// rather than replace a file already there, even one created meanwhile
+func writeFileExclusive(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp, 0644); err != nil {
+		return err
+	}
+
+	// Unlike a rename, linking never replaces an existing file
+	return os.Link(tmp, path)
+}
+
+// LoadEvents reads every event file under the events directory, sorted
+// chronologically. Files without front matter are skipped.
+func LoadEvents() ([]config.Event, error) {
+	var events []config.Event
+
+	err := filepath.WalkDir(config.EventsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+
+		event, ok, err := readEventFile(path)
+		if err != nil {
+			return err
+		}
+		if ok {
+			events = append(events, event)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to load events: %w", err)
+	}
+
+	SortEvents(events)
+	return events, nil
+}
+
+// SortEvents orders events chronologically, then by name. Approximate
+// dates sort from the start of their month or year, ahead of exact dates
+// on the same day.
+func SortEvents(events []config.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].DaysSinceZero != events[j].DaysSinceZero {
+			return events[i].DaysSinceZero < events[j].DaysSinceZero
+		}
+		if ri, rj := precisionRank(events[i]), precisionRank(events[j]); ri != rj {
+			return ri < rj
+		}
+		return events[i].Name < events[j].Name
+	})
+}
+
+// EventsOnDay returns the events of a calendar taking place on the given
+// day, including ranged events that started earlier and are still ongoing
+// and occurrences of recurring events.
+func EventsOnDay(events []config.Event, cal config.Calendar, daysInYear int, daysSinceZero int) []config.Event {
+	return OccurrencesBetween(events, cal, daysInYear, daysSinceZero, daysSinceZero)
+}
+
+// precisionRank orders coarser dates before finer ones
+func precisionRank(event config.Event) int {
+	switch event.Precision {
+	case config.PrecisionCirca:
+		return 0
+	case config.PrecisionYear:
+		return 1
+	case config.PrecisionMonth:
+		return 2
+	}
+	return 3
+}
@@ -0,0 +1,191 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/sksmith/gmcli/internal/config"
+	"gopkg.in/yaml.v2"
+)
+
+// Event templates live in the templates directory with this suffix
+const (
+	templateSuffix      = ".md.tmpl"
+	DefaultTemplateName = "event"
+)
+
+// templateHeader matches a leading template comment declaring fields, e.g.
+//
+//	{{/*
+//	fields:
+//	  - name: victor
+//	    prompt: Who won?
+//	*/}}
+var templateHeader = regexp.MustCompile(`^\s*\{\{-?\s*/\*([\s\S]*?)\*/\s*-?\}\}`)
+
+// fieldName restricts field names to ones usable as {{.Fields.name}}
+var fieldName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// LoadTemplates discovers every event template, the default one first and
+// the rest by name.
+func LoadTemplates() ([]config.EventTemplate, error) {
+	paths, err := filepath.Glob(filepath.Join(config.TemplatesDir, "*"+templateSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	var templates []config.EventTemplate
+	for _, path := range paths {
+		tmpl, err := loadTemplate(path)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, tmpl)
+	}
+
+	sort.SliceStable(templates, func(i, j int) bool {
+		if (templates[i].Name == DefaultTemplateName) != (templates[j].Name == DefaultTemplateName) {
+			return templates[i].Name == DefaultTemplateName
+		}
+		return templates[i].Name < templates[j].Name
+	})
+
+	return templates, nil
+}
+
+// FindTemplate returns the template with the given name, ignoring case.
+func FindTemplate(templates []config.EventTemplate, name string) (config.EventTemplate, bool) {
+	for _, tmpl := range templates {
+		if strings.EqualFold(tmpl.Name, name) {
+			return tmpl, true
+		}
+	}
+	return config.EventTemplate{}, false
+}
+
+// loadTemplate reads a template's field declarations
+func loadTemplate(path string) (config.EventTemplate, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return config.EventTemplate{}, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	tmpl := config.EventTemplate{
+		Name: strings.TrimSuffix(filepath.Base(path), templateSuffix),
+		Path: path,
+	}
+
+	fields, err := parseTemplateHeader(string(content))
+	if err != nil {
+		return config.EventTemplate{}, fmt.Errorf("failed to parse fields of %s: %w", path, err)
+	}
+	tmpl.Fields = fields
+
+	return tmpl, nil
+}
+
+// parseTemplateHeader reads the fields declared in a template's header
+// comment. Templates without a header, or whose leading comment isn't a
+// field declaration, have no extra fields.
+func parseTemplateHeader(content string) ([]config.TemplateField, error) {
+	match := templateHeader.FindStringSubmatch(content)
+	if match == nil || !strings.HasPrefix(strings.TrimSpace(match[1]), "fields:") {
+		return nil, nil
+	}
+
+	var header struct {
+		Fields []config.TemplateField `yaml:"fields"`
+	}
+	if err := yaml.Unmarshal([]byte(match[1]), &header); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, field := range header.Fields {
+		if !fieldName.MatchString(field.Name) {
+			return nil, fmt.Errorf("field name '%s' must be letters, digits and underscores", field.Name)
+		}
+		if seen[field.Name] {
+			return nil, fmt.Errorf("field '%s' is declared twice", field.Name)
+		}
+		seen[field.Name] = true
+	}
+
+	return header.Fields, nil
+}
+
+// eventTemplateFuncs returns the calendar-aware functions available to
+// event templates. Dates are passed as days since year 0 and come last, so
+// they can be piped, e.g. {{.DaysSinceZero | addDays 7 | formatDate "{day} {monthName}"}}.
+func eventTemplateFuncs(cfg config.Config, cal config.Calendar) template.FuncMap {
+	return template.FuncMap{
+		"monthName": func(month int) string {
+			return MonthName(cal, month)
+		},
+		"ageName": func(abbrev string) string {
+			return AgeName(cal, abbrev)
+		},
+		"season": func(month int) string {
+			return Season(cal, month)
+		},
+		"weekday": func(days int) string {
+			return Weekday(cal, days)
+		},
+		"moonPhase": func(days int) string {
+			var phases []string
+			for _, moon := range cal.Moons {
+				phase := MoonPhase(moon, days)
+				if len(cal.Moons) > 1 {
+					phase = moon.Name + ": " + phase
+				}
+				phases = append(phases, phase)
+			}
+			return strings.Join(phases, ", ")
+		},
+		"describeDate": func(event config.Event) string {
+			return DescribeDate(cal, event)
+		},
+		"formatDate": func(layout string, days int) (string, error) {
+			return FormatDate(cal, cfg.DaysInYear, layout, days)
+		},
+		"addDays": func(n, days int) int {
+			return days + n
+		},
+		"daysBetween": func(from, to int) int {
+			return to - from
+		},
+		"convertTo": func(calendar string, days int) (string, error) {
+			target, err := FindCalendar(cfg, calendar)
+			if err != nil {
+				return "", err
+			}
+			date, err := DateFromDays(target, cfg.DaysInYear, ConvertDays(cal, target, days))
+			if err != nil {
+				return "", err
+			}
+			return date.DateString(), nil
+		},
+	}
+}
+
+// parseEventTemplate loads the named template ready for rendering
+func parseEventTemplate(name string, funcs template.FuncMap) (*template.Template, error) {
+	if name == "" {
+		name = DefaultTemplateName
+	}
+	if strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return nil, fmt.Errorf("invalid template name '%s'", name)
+	}
+
+	path := filepath.Join(config.TemplatesDir, name+templateSuffix)
+	tmpl, err := template.New(filepath.Base(path)).Option("missingkey=zero").Funcs(funcs).ParseFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load template: %w", err)
+	}
+	return tmpl, nil
+}
@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/sksmith/gmcli/internal/config"
+)
+
+// DefaultPace is the pace used when none is given
+const DefaultPace = "normal"
+
+// DefaultTravelModes are the ways of travelling used when the config
+// doesn't list its own
+var DefaultTravelModes = []config.TravelMode{
+	{Name: "foot", Paces: map[string]float64{"slow": 2, "normal": 3, "fast": 4}, HoursPerDay: 8},
+	{Name: "horse", Paces: map[string]float64{"slow": 4, "normal": 5, "fast": 6}, HoursPerDay: 8},
+	{Name: "ship", Paces: map[string]float64{"slow": 2, "normal": 4, "fast": 6}, HoursPerDay: 24},
+}
+
+// TravelModes returns the configured ways of travelling, or the defaults.
+func TravelModes(cfg config.Config) []config.TravelMode {
+	if len(cfg.TravelModes) > 0 {
+		return cfg.TravelModes
+	}
+	return DefaultTravelModes
+}
+
+// FindTravelMode returns the travel mode with the given name, ignoring case.
+func FindTravelMode(modes []config.TravelMode, name string) (config.TravelMode, bool) {
+	for _, mode := range modes {
+		if strings.EqualFold(mode.Name, name) {
+			return mode, true
+		}
+	}
+	return config.TravelMode{}, false
+}
+
+// PaceNames returns a mode's paces from slowest to fastest.
+func PaceNames(mode config.TravelMode) []string {
+	names := make([]string, 0, len(mode.Paces))
+	for name := range mode.Paces {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return mode.Paces[names[i]] < mode.Paces[names[j]]
+	})
+	return names
+}
+
+// findPace returns a mode's pace with the given name, ignoring case, and
+// its speed
+func findPace(mode config.TravelMode, name string) (string, float64, bool) {
+	for pace, speed := range mode.Paces {
+		if strings.EqualFold(pace, name) {
+			return pace, speed, true
+		}
+	}
+	return "", 0, false
+}
+
+// TravelPlan describes a journey to work out.
+type TravelPlan struct {
+	Distance    float64 // miles
+	Mode        string
+	Pace        string  // blank for DefaultPace
+	HoursPerDay float64 // zero for the mode's usual hours
+	RestDays    int     // days spent resting along the way
+}
+
+// TravelResult is a worked out journey.
+type TravelResult struct {
+	Plan        TravelPlan
+	Departure   config.Event
+	Arrival     config.Event
+	MilesPerDay float64
+	TravelDays  int // days on the road, the last one possibly partial
+	TotalDays   int // travel and rest days
+}
+
+// String summarises the journey, e.g. "230 miles by horse at normal pace:
+// 40 miles a day, 6 days travelling and 1 resting".
+func (r TravelResult) String() string {
+	return fmt.Sprintf("%g miles by %s at %s pace: %g miles a day, %s travelling and %d resting",
+		r.Plan.Distance, r.Plan.Mode, r.Plan.Pace, r.MilesPerDay, PluralDays(r.TravelDays), r.Plan.RestDays)
+}
+
+// PlanTravel works out when a journey leaving on the departure date
+// arrives. Travel starts on the departure day itself, so a journey of a
+// single day arrives the same day.
+func PlanTravel(cfg config.Config, cal config.Calendar, departure config.Event, plan TravelPlan) (TravelResult, error) {
+	mode, ok := FindTravelMode(TravelModes(cfg), plan.Mode)
+	if !ok {
+		return TravelResult{}, fmt.Errorf("unknown travel mode '%s'", plan.Mode)
+	}
+	plan.Mode = mode.Name
+
+	if plan.Pace == "" {
+		plan.Pace = DefaultPace
+	}
+	pace, speed, ok := findPace(mode, plan.Pace)
+	if !ok || speed <= 0 {
+		return TravelResult{}, fmt.Errorf("%s has no %s pace (try %s)", mode.Name, plan.Pace, strings.Join(PaceNames(mode), ", "))
+	}
+	plan.Pace = pace
+
+	if plan.HoursPerDay == 0 {
+		plan.HoursPerDay = mode.HoursPerDay
+	}
+	if plan.HoursPerDay <= 0 || plan.HoursPerDay > 24 {
+		return TravelResult{}, fmt.Errorf("hours per day must be more than 0 and at most 24")
+	}
+	if plan.Distance < 0 {
+		return TravelResult{}, fmt.Errorf("distance cannot be negative")
+	}
+	if plan.RestDays < 0 {
+		return TravelResult{}, fmt.Errorf("rest days cannot be negative")
+	}
+	if departure.IsApproximate() {
+		return TravelResult{}, fmt.Errorf("the departure must be an exact day")
+	}
+
+	result := TravelResult{
+		Plan:        plan,
+		Departure:   departure,
+		MilesPerDay: speed * plan.HoursPerDay,
+	}
+	result.TravelDays = int(math.Ceil(plan.Distance / result.MilesPerDay))
+	result.TotalDays = result.TravelDays + plan.RestDays
+
+	last := departure.DaysSinceZero
+	if result.TotalDays > 0 {
+		last += result.TotalDays - 1
+	}
+	arrival, err := DateFromDays(cal, cfg.DaysInYear, last)
+	if err != nil {
+		return TravelResult{}, err
+	}
+	result.Arrival = arrival
+	return result, nil
+}
+
+// LogJourney records a journey as a departure event followed by an
+// arrival event, both attributed to the party if one is given.
+func LogJourney(cfg config.Config, cal config.Calendar, result TravelResult, destination, party string) error {
+	destination = strings.TrimSpace(destination)
+	if destination == "" {
+		return fmt.Errorf("destination cannot be empty")
+	}
+
+	departure := result.Departure
+	departure.ID = NewEventID()
+	departure.Name = "Depart for " + destination
+	departure.Party = party
+	departure.Tags = []string{"travel"}
+	departure.Body = result.String() + "."
+
+	arrival := result.Arrival
+	arrival.ID = NewEventID()
+	arrival.Name = "Arrive at " + destination
+	arrival.Party = party
+	arrival.Tags = []string{"travel"}
+	arrival.Body = departure.Body
+	arrival.Links = []config.EventLink{{Relation: RelationFollowed, Target: departure.ID}}
+
+	// The departure goes first so the arrival can link to it
+	if err := CreateEvent(cfg, cal, departure); err != nil {
+		return fmt.Errorf("failed to create departure: %w", err)
+	}
+	if err := CreateEvent(cfg, cal, arrival); err != nil {
+		return fmt.Errorf("failed to create arrival: %w", err)
+	}
+	return nil
+}
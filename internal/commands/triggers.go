@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sksmith/gmcli/internal/config"
+)
+
+// Kinds of digest entries
+const (
+	DigestEvent   = "event"
+	DigestOverdue = "overdue"
+	DigestHoliday = "holiday"
+	DigestMoon    = "moon"
+	DigestMonth   = "month"
+	DigestSeason  = "season"
+	DigestYear    = "year"
+	DigestAge     = "age"
+)
+
+// DigestEntry is something the campaign clock passed while advancing.
+type DigestEntry struct {
+	Kind    string
+	Days    int    // day it happened on, in days since 0
+	Date    string // AAYYYY-MM-DD
+	Message string
+	Event   *config.Event // set for event entries
+}
+
+// ClockDigest lists everything the clock passes when moving from one day
+// to a later one: the events taking place after from up to and including
+// to, scheduled events left over from before, holidays, full and new
+// moons, and the start of months, seasons, years and ages. Entries are in
+// chronological order.
+func ClockDigest(cfg config.Config, cal config.Calendar, from, to int, events []config.Event) []DigestEntry {
+	if to <= from {
+		return nil
+	}
+
+	var entries []DigestEntry
+	add := func(kind string, days int, event *config.Event, format string, args ...interface{}) {
+		date, err := DateFromDays(cal, cfg.DaysInYear, days)
+		if err != nil {
+			return
+		}
+		entries = append(entries, DigestEntry{
+			Kind:    kind,
+			Days:    days,
+			Date:    date.DateString(),
+			Message: fmt.Sprintf(format, args...),
+			Event:   event,
+		})
+	}
+
+	for _, event := range events {
+		if event.CalendarName == cal.Name && event.Status == config.StatusScheduled && dueDay(event) <= from {
+			event := event
+			add(DigestOverdue, dueDay(event), &event, "%s was still scheduled", event.Name)
+		}
+	}
+	for _, occ := range OccurrencesBetween(events, cal, cfg.DaysInYear, from+1, to) {
+		if occ.DaysSinceZero <= from {
+			continue // already under way
+		}
+		occ := occ
+		add(DigestEvent, occ.DaysSinceZero, &occ, "%s", occ.Name)
+	}
+
+	previous, err := DateFromDays(cal, cfg.DaysInYear, from)
+	if err != nil {
+		return entries
+	}
+	for days := from + 1; days <= to; days++ {
+		date, err := DateFromDays(cal, cfg.DaysInYear, days)
+		if err != nil {
+			break
+		}
+
+		if date.AgeAbbrev != previous.AgeAbbrev {
+			add(DigestAge, days, nil, "%s begins", AgeName(cal, date.AgeAbbrev))
+		}
+		if date.Year != previous.Year {
+			add(DigestYear, days, nil, "Year %s%04d begins", date.AgeAbbrev, date.Year)
+		}
+		if date.Month != previous.Month {
+			if name := MonthName(cal, date.Month); name != "" {
+				add(DigestMonth, days, nil, "%s begins", name)
+			}
+			season := Season(cal, date.Month)
+			if season != "" && season != Season(cal, previous.Month) {
+				add(DigestSeason, days, nil, "%s begins", season)
+			}
+		}
+		for _, holiday := range cal.Holidays {
+			if holiday.Month == date.Month && holiday.Day == date.Day {
+				add(DigestHoliday, days, nil, "%s", holiday.Name)
+			}
+		}
+		for _, moon := range cal.Moons {
+			phase := MoonPhase(moon, days)
+			if (phase == "full moon" || phase == "new moon") && phase != MoonPhase(moon, days-1) {
+				add(DigestMoon, days, nil, "%s: %s", moon.Name, phase)
+			}
+		}
+
+		previous = date
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Days < entries[j].Days
+	})
+	return entries
+}
+
+// dueDay returns the day a scheduled event is due by: its start, or the
+// last day it may fall on for approximate dates
+func dueDay(event config.Event) int {
+	start, end := event.Window()
+	if event.IsApproximate() {
+		return end
+	}
+	return start
+}
+
+// MarkOccurred flags the scheduled events of a digest attributed to a
+// party (or to none, for a blank party) as occurred and returns how many
+// were updated. Occurrences of repeating events are left alone, since the
+// rule goes on after them.
+func MarkOccurred(entries []DigestEntry, party string) (int, error) {
+	marked := 0
+	for _, entry := range entries {
+		event := entry.Event
+		if event == nil || event.Status != config.StatusScheduled || event.Recurrence != nil {
+			continue
+		}
+		if !strings.EqualFold(event.Party, party) {
+			continue
+		}
+
+		event.Status = config.StatusOccurred
+		if err := UpdateEvent(*event); err != nil {
+			return marked, fmt.Errorf("failed to mark %s as occurred: %w", event.Name, err)
+		}
+		marked++
+	}
+	return marked, nil
+}
+
+// ParseStatus reads an event status: "scheduled" or "occurred", or their
+// first letters. Blank input gives a blank status.
+func ParseStatus(input string) (string, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	switch {
+	case input == "":
+		return "", nil
+	case strings.HasPrefix(config.StatusScheduled, input):
+		return config.StatusScheduled, nil
+	case strings.HasPrefix(config.StatusOccurred, input):
+		return config.StatusOccurred, nil
+	}
+	return "", fmt.Errorf("unknown status '%s', expected %s or %s", input, config.StatusScheduled, config.StatusOccurred)
+}
+
+// SetEventStatus flags an existing event as scheduled or occurred, or
+// clears its status for a blank one.
+func SetEventStatus(event config.Event, status string) error {
+	event.Status = status
+	if err := UpdateEvent(event); err != nil {
+		return fmt.Errorf("failed to set the status of %s: %w", event.Name, err)
+	}
+	return nil
+}
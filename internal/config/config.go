@@ -10,42 +10,144 @@ import (
 
 const (
 	defaultConfigPath = "config.yaml"
-	templatesDir      = "templates"
-	eventsDir         = "events"
+	charactersPath    = "characters.yaml"
+	locationsPath     = "locations.yaml"
+	sessionsPath      = "sessions.yaml"
+	countdownsPath    = "countdowns.yaml"
+	partiesPath       = "parties.yaml"
+	historyPath       = "history.yaml"
+
+	// TemplatesDir holds the markdown templates used to render events
+	TemplatesDir = "templates"
+	// EventsDir holds the generated event files
+	EventsDir = "events"
+	// EncountersDir holds the random encounter tables
+	EncountersDir = "encounters"
+	// DefaultEventPath is the event file layout used when the config
+	// doesn't set one
+	DefaultEventPath = "events/{slug}_{days}.md"
 )
 
 // Load loads configuration from file.
 func Load() (Config, error) {
 	var cfg Config
+	err := loadYAML(defaultConfigPath, &cfg, "config")
+	return cfg, err
+}
+
+// Save saves configuration to file.
+func Save(cfg Config) error {
+	return saveYAML(defaultConfigPath, cfg, "config")
+}
+
+// LoadCharacters loads the character registry.
+func LoadCharacters() ([]Character, error) {
+	var characters []Character
+	err := loadYAML(charactersPath, &characters, "characters")
+	return characters, err
+}
+
+// SaveCharacters saves the character registry.
+func SaveCharacters(characters []Character) error {
+	return saveYAML(charactersPath, characters, "characters")
+}
 
+// LoadLocations loads the locations registry.
+func LoadLocations() ([]Location, error) {
+	var locations []Location
+	err := loadYAML(locationsPath, &locations, "locations")
+	return locations, err
+}
+
+// SaveLocations saves the locations registry.
+func SaveLocations(locations []Location) error {
+	return saveYAML(locationsPath, locations, "locations")
+}
+
+// LoadSessions loads the session log.
+func LoadSessions() ([]Session, error) {
+	var sessions []Session
+	err := loadYAML(sessionsPath, &sessions, "sessions")
+	return sessions, err
+}
+
+// SaveSessions saves the session log.
+func SaveSessions(sessions []Session) error {
+	return saveYAML(sessionsPath, sessions, "sessions")
+}
+
+// LoadCountdowns loads the countdowns.
+func LoadCountdowns() ([]Countdown, error) {
+	var countdowns []Countdown
+	err := loadYAML(countdownsPath, &countdowns, "countdowns")
+	return countdowns, err
+}
+
+// SaveCountdowns saves the countdowns.
+func SaveCountdowns(countdowns []Countdown) error {
+	return saveYAML(countdownsPath, countdowns, "countdowns")
+}
+
+// LoadParties loads the parties.
+func LoadParties() ([]Party, error) {
+	var parties []Party
+	err := loadYAML(partiesPath, &parties, "parties")
+	return parties, err
+}
+
+// SaveParties saves the parties.
+func SaveParties(parties []Party) error {
+	return saveYAML(partiesPath, parties, "parties")
+}
+
+// LoadHistory loads the operation history, oldest first.
+func LoadHistory() ([]HistoryEntry, error) {
+	var history []HistoryEntry
+	err := loadYAML(historyPath, &history, "history")
+	return history, err
+}
+
+// SaveHistory saves the operation history.
+func SaveHistory(history []HistoryEntry) error {
+	return saveYAML(historyPath, history, "history")
+}
+
+// StateFiles lists the workspace files holding campaign state besides the
+// event files.
+func StateFiles() []string {
+	return []string{defaultConfigPath, charactersPath, locationsPath, sessionsPath, countdownsPath, partiesPath}
+}
+
+// loadYAML reads a YAML file into v, leaving v untouched if the file
+// doesn't exist yet
+func loadYAML(path string, v interface{}, what string) error {
 	// Check if file exists
-	if _, err := os.Stat(defaultConfigPath); os.IsNotExist(err) {
-		// Return empty config if file doesn't exist yet
-		return cfg, nil
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
 	}
 
 	// Read and parse file
-	data, err := os.ReadFile(defaultConfigPath)
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return cfg, fmt.Errorf("failed to read config file: %w", err)
+		return fmt.Errorf("failed to read %s file: %w", what, err)
 	}
 
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return cfg, fmt.Errorf("failed to parse config file: %w", err)
+	if err := yaml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s file: %w", what, err)
 	}
 
-	return cfg, nil
+	return nil
 }
 
-// Save saves configuration to file.
-func Save(cfg Config) error {
-	data, err := yaml.Marshal(cfg)
+// saveYAML writes v to a YAML file
+func saveYAML(path string, v interface{}, what string) error {
+	data, err := yaml.Marshal(v)
 	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
+		return fmt.Errorf("failed to marshal %s: %w", what, err)
 	}
 
-	if err := os.WriteFile(defaultConfigPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write config file: %w", err)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s file: %w", what, err)
 	}
 
 	return nil
@@ -53,7 +155,7 @@ func Save(cfg Config) error {
 
 // EnsureDirectories creates necessary directories.
 func EnsureDirectories() error {
-	dirs := []string{templatesDir, eventsDir}
+	dirs := []string{TemplatesDir, EventsDir, EncountersDir}
 
 	for _, dir := range dirs {
 		if err := os.MkdirAll(dir, 0755); err != nil {
@@ -62,17 +164,57 @@ func EnsureDirectories() error {
 	}
 
 	// Create default template if it doesn't exist
-	templatePath := filepath.Join(templatesDir, "event.md.tmpl")
+	templatePath := filepath.Join(TemplatesDir, "event.md.tmpl")
 	if _, err := os.Stat(templatePath); os.IsNotExist(err) {
 		defaultTemplate := `# {{.Name}}
 
 ## Details
 - Calendar: {{.CalendarName}} ({{.CalendarAbbrev}})
-- Date: {{.AgeAbbrev}}{{.Year}}-{{printf "%02d" .Month}}-{{printf "%02d" .Day}}
+- Date: {{describeDate .Event}}
 - Days Since Year 0: {{.DaysSinceZero}}
+{{- if .IsRanged}}
+- End Date: {{.EndAgeAbbrev}}{{.EndYear}}-{{printf "%02d" .EndMonth}}-{{printf "%02d" .EndDay}}
+- Duration: {{.Length}} days
+{{- end}}
+{{- if .Recurrence}}
+- Repeats: {{.Recurrence}}
+{{- end}}
+{{- if .Category}}
+- Category: {{.Category}}
+{{- end}}
+{{- if .Tags}}
+- Tags: {{range $i, $tag := .Tags}}{{if $i}}, {{end}}{{$tag}}{{end}}
+{{- end}}
+{{- if .LocationPaths}}
+- Locations:
+{{- range .LocationPaths}}
+  - {{.}}
+{{- end}}
+{{- end}}
+{{- if .Characters}}
+- Characters: {{range $i, $name := .Characters}}{{if $i}}, {{end}}{{$name}}{{end}}
+{{- end}}
+
+{{- if .CharactersAlive}}
+
+## Characters Alive
+{{- range .CharactersAlive}}
+- {{.Name}}{{if .Species}} ({{.Species}}){{end}}, age {{.Age}}
+{{- end}}
+{{- end}}
 
 ## Description
+{{- if .Description}}
+{{.Description}}
+{{- else}}
 <!-- Add event description here -->
+{{- end}}
+
+:::gm
+## GM Notes
+<!-- Secret information only the GM should see. This fenced section is
+left out of player views and exports. -->
+:::
 
 `
 		if err := os.WriteFile(templatePath, []byte(defaultTemplate), 0644); err != nil {
@@ -1,9 +1,25 @@
 package config
 
+import "fmt"
+
 // Config represents the overall configuration.
 type Config struct {
 	DaysInYear int        `yaml:"days_in_year"`
+	EventPath  string     `yaml:"event_path,omitempty"` // file layout pattern, see DefaultEventPath
 	Calendars  []Calendar `yaml:"calendars"`
+
+	// Ways of travelling for the travel calculator, replacing the defaults
+	TravelModes []TravelMode `yaml:"travel_modes,omitempty"`
+
+	// How many operations the history keeps for undo
+	HistoryLimit int `yaml:"history_limit,omitempty"`
+}
+
+// TravelMode is a way of travelling and its speed at each pace.
+type TravelMode struct {
+	Name        string             `yaml:"name"`
+	Paces       map[string]float64 `yaml:"paces"`                   // miles per hour by pace, e.g. slow, normal, fast
+	HoursPerDay float64            `yaml:"hours_per_day,omitempty"` // usual hours travelled a day
 }
 
 // Calendar represents one fantasy calendar.
@@ -14,6 +30,17 @@ type Calendar struct {
 	TotalYears   int     `yaml:"total_years"` // total years available for ages
 	Ages         []Age   `yaml:"ages"`
 	Months       []Month `yaml:"months"`
+
+	// The campaign's current in-world date, AAYYYY-MM-DD
+	CurrentDate string `yaml:"current_date,omitempty"`
+
+	// Optional extras used by event templates
+	Weekdays    []string `yaml:"weekdays,omitempty"` // day 0 falls on the first weekday
+	Moons       []Moon   `yaml:"moons,omitempty"`
+	EpochOffset int      `yaml:"epoch_offset,omitempty"` // days to add to line up with other calendars
+
+	// Days celebrated every year, reported when the campaign clock passes them
+	Holidays []Holiday `yaml:"holidays,omitempty"`
 }
 
 // Age represents an age in the calendar.
@@ -29,18 +56,365 @@ type Month struct {
 	Name     string `yaml:"name"`
 	Days     int    `yaml:"days"`
 	Previous string `yaml:"previous_month,omitempty"`
+	Season   string `yaml:"season,omitempty"`
 }
 
+// Moon is a moon whose phases repeat every Cycle days.
+type Moon struct {
+	Name   string `yaml:"name"`
+	Cycle  int    `yaml:"cycle"`            // days from one new moon to the next
+	Offset int    `yaml:"offset,omitempty"` // day of a new moon
+}
+
+// Holiday is a day celebrated every year.
+type Holiday struct {
+	Name  string `yaml:"name"`
+	Month int    `yaml:"month"`
+	Day   int    `yaml:"day"`
+}
+
+// Date precisions
+const (
+	PrecisionDay   = "day"
+	PrecisionMonth = "month"
+	PrecisionYear  = "year"
+	PrecisionCirca = "circa"
+)
+
+// Event statuses
+const (
+	StatusScheduled = "scheduled"
+	StatusOccurred  = "occurred"
+)
+
 // Event represents an event to be created.
 type Event struct {
-	CalendarName   string
-	CalendarAbbrev string
-	AgeAbbrev      string
-	Year           int
-	Month          int
-	Day            int
-	DaysSinceZero  int
-	Name           string
+	ID             string `yaml:"id,omitempty"` // stable identifier used by links
+	CalendarName   string `yaml:"calendar"`
+	CalendarAbbrev string `yaml:"calendar_abbreviation"`
+	AgeAbbrev      string `yaml:"age"`
+	Year           int    `yaml:"year"`
+	Month          int    `yaml:"month"`
+	Day            int    `yaml:"day"`
+	DaysSinceZero  int    `yaml:"days_since_zero"`
+	Name           string `yaml:"name"`
+
+	// Optional end date for events spanning several days (inclusive)
+	EndAgeAbbrev     string `yaml:"end_age,omitempty"`
+	EndYear          int    `yaml:"end_year,omitempty"`
+	EndMonth         int    `yaml:"end_month,omitempty"`
+	EndDay           int    `yaml:"end_day,omitempty"`
+	EndDaysSinceZero int    `yaml:"end_days_since_zero,omitempty"`
+	DurationDays     int    `yaml:"duration_days,omitempty"`
+
+	// How exactly the date is known. Approximate dates keep the first day
+	// of their month or year in DaysSinceZero for sorting, leave unknown
+	// parts zero, and record the window of days they may fall in.
+	Precision    string `yaml:"precision,omitempty"` // "" (exact day), month, year or circa
+	CircaYears   int    `yaml:"circa_years,omitempty"`
+	EarliestDays int    `yaml:"earliest_days,omitempty"`
+	LatestDays   int    `yaml:"latest_days,omitempty"`
+
+	// Optional rule repeating the event after its first occurrence
+	Recurrence *Recurrence `yaml:"recurrence,omitempty"`
+
+	Tags       []string `yaml:"tags,omitempty"`
+	Category   string   `yaml:"category,omitempty"`
+	Locations  []string `yaml:"locations,omitempty"`  // location names
+	Characters []string `yaml:"characters,omitempty"` // names of characters involved
+
+	// Party the event happened to, when parties keep their own clocks
+	Party string `yaml:"party,omitempty"`
+
+	Links []EventLink `yaml:"links,omitempty"`
+
+	// Hidden from player views and exports when set
+	GMOnly bool `yaml:"gm_only,omitempty"`
+
+	// Scheduled events are still to come; they become occurred once the
+	// campaign clock passes them
+	Status string `yaml:"status,omitempty"`
+
+	// Characters who know about the event and when they learned of it
+	KnownBy []Knowledge `yaml:"known_by,omitempty"`
+
+	// Template the event was rendered with and the extra fields it asked for
+	Template string            `yaml:"template,omitempty"`
+	Fields   map[string]string `yaml:"fields,omitempty"`
+
+	// Populated when an event is loaded from disk
+	Path string `yaml:"-"`
+	Body string `yaml:"-"`
+}
+
+// EventLink is a typed relation from one event to another.
+type EventLink struct {
+	Relation string `yaml:"relation"` // caused, followed, concurrent-with or contradicts
+	Target   string `yaml:"target"`   // ID of the related event
+}
+
+// Knowledge records when a character learned of an event.
+type Knowledge struct {
+	Character   string `yaml:"character"`
+	Learned     string `yaml:"learned"` // AAYYYY-MM-DD
+	LearnedDays int    `yaml:"learned_days"`
+}
+
+// EventTemplate is a markdown template events can be rendered with.
+type EventTemplate struct {
+	Name   string          `yaml:"-"` // file name without .md.tmpl
+	Path   string          `yaml:"-"`
+	Fields []TemplateField `yaml:"fields"`
+}
+
+// TemplateField is an extra value a template asks for when creating an
+// event.
+type TemplateField struct {
+	Name    string `yaml:"name"`
+	Prompt  string `yaml:"prompt,omitempty"`
+	Default string `yaml:"default,omitempty"`
+}
+
+// HasTag reports whether the event carries the given tag.
+func (e Event) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Recurrence describes how an event repeats.
+type Recurrence struct {
+	Frequency string `yaml:"frequency"`            // daily, monthly or annually
+	Interval  int    `yaml:"interval,omitempty"`   // every N days, months or years
+	Day       int    `yaml:"day,omitempty"`        // day of month for monthly and annual rules
+	Month     int    `yaml:"month,omitempty"`      // month for annual rules
+	Ordinal   int    `yaml:"ordinal,omitempty"`    // for monthly rules on a weekday: 1 to 5, or -1 for the last
+	Weekday   string `yaml:"weekday,omitempty"`    // weekday monthly rules fall on instead of a day
+	Until     int    `yaml:"until_days,omitempty"` // optional last day (days since 0)
+}
+
+// String returns a short description of the rule.
+func (r Recurrence) String() string {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	switch r.Frequency {
+	case "daily":
+		if interval == 1 {
+			return "every day"
+		}
+		return fmt.Sprintf("every %d days", interval)
+	case "monthly":
+		on := fmt.Sprintf("day %d", r.Day)
+		if r.Weekday != "" {
+			on = fmt.Sprintf("the %s %s", ordinalName(r.Ordinal), r.Weekday)
+		}
+		if interval == 1 {
+			return "monthly on " + on
+		}
+		return fmt.Sprintf("every %d months on %s", interval, on)
+	case "annually":
+		if interval == 1 {
+			return fmt.Sprintf("annually on %02d-%02d", r.Month, r.Day)
+		}
+		return fmt.Sprintf("every %d years on %02d-%02d", interval, r.Month, r.Day)
+	}
+	return r.Frequency
+}
+
+// ordinalName words a weekday ordinal, e.g. "2nd" or "last"
+func ordinalName(n int) string {
+	switch n {
+	case -1:
+		return "last"
+	case 1:
+		return "1st"
+	case 2:
+		return "2nd"
+	case 3:
+		return "3rd"
+	}
+	return fmt.Sprintf("%dth", n)
+}
+
+// IsRanged reports whether the event spans more than one day.
+func (e Event) IsRanged() bool {
+	return e.EndDaysSinceZero > e.DaysSinceZero
+}
+
+// LastDay returns the days since 0 of the final day covered by the event.
+func (e Event) LastDay() int {
+	if e.IsRanged() {
+		return e.EndDaysSinceZero
+	}
+	return e.DaysSinceZero
+}
+
+// Length returns the number of days the event covers.
+func (e Event) Length() int {
+	return e.LastDay() - e.DaysSinceZero + 1
+}
+
+// Covers reports whether the event takes place on the given day. Events
+// with approximate dates don't cover any particular day.
+func (e Event) Covers(daysSinceZero int) bool {
+	if e.IsApproximate() {
+		return false
+	}
+	return daysSinceZero >= e.DaysSinceZero && daysSinceZero <= e.LastDay()
+}
+
+// IsApproximate reports whether only part of the event's date is known.
+func (e Event) IsApproximate() bool {
+	return e.Precision != "" && e.Precision != PrecisionDay
+}
+
+// Window returns the first and last day the event may take place on.
+func (e Event) Window() (int, int) {
+	if e.IsApproximate() {
+		return e.EarliestDays, e.LatestDays
+	}
+	return e.DaysSinceZero, e.LastDay()
+}
+
+// DateString returns the start date in AAYYYY-MM-DD format, shortened to
+// AAYYYY-MM or AAYYYY for approximate dates, e.g. "c. DF0100 ±10".
+func (e Event) DateString() string {
+	switch e.Precision {
+	case PrecisionMonth:
+		return fmt.Sprintf("%s%04d-%02d", e.AgeAbbrev, e.Year, e.Month)
+	case PrecisionYear:
+		return fmt.Sprintf("%s%04d", e.AgeAbbrev, e.Year)
+	case PrecisionCirca:
+		if e.CircaYears > 0 {
+			return fmt.Sprintf("c. %s%04d ±%d", e.AgeAbbrev, e.Year, e.CircaYears)
+		}
+		return fmt.Sprintf("c. %s%04d", e.AgeAbbrev, e.Year)
+	}
+	return fmt.Sprintf("%s%04d-%02d-%02d", e.AgeAbbrev, e.Year, e.Month, e.Day)
+}
+
+// DateDescription words the date for listings, e.g. "sometime in DF0100".
+func (e Event) DateDescription() string {
+	switch e.Precision {
+	case PrecisionMonth, PrecisionYear:
+		return "sometime in " + e.DateString()
+	case PrecisionCirca:
+		if e.CircaYears > 0 {
+			return fmt.Sprintf("around %s%04d, give or take %d years", e.AgeAbbrev, e.Year, e.CircaYears)
+		}
+		return fmt.Sprintf("around %s%04d", e.AgeAbbrev, e.Year)
+	}
+	return e.DateString()
+}
+
+// EndDateString returns the end date in AAYYYY-MM-DD format, or an empty
+// string for single-day events.
+func (e Event) EndDateString() string {
+	if !e.IsRanged() {
+		return ""
+	}
+	return fmt.Sprintf("%s%04d-%02d-%02d", e.EndAgeAbbrev, e.EndYear, e.EndMonth, e.EndDay)
+}
+
+// Character represents a person tracked across the campaign.
+type Character struct {
+	Name     string `yaml:"name"`
+	Calendar string `yaml:"calendar"`
+	Born     string `yaml:"born"`           // AAYYYY-MM-DD
+	Died     string `yaml:"died,omitempty"` // AAYYYY-MM-DD
+	Species  string `yaml:"species,omitempty"`
+	Lifespan int    `yaml:"lifespan,omitempty"` // typical lifespan of the species in years
+}
+
+// Location represents a place in the world. Locations nest through their
+// parent, e.g. a building inside a city inside a region.
+type Location struct {
+	Name   string `yaml:"name"`
+	Kind   string `yaml:"kind,omitempty"`   // continent, region, city, building...
+	Parent string `yaml:"parent,omitempty"` // name of the enclosing location
+}
+
+// Session is one real-world game session and the in-game days it covered.
+type Session struct {
+	Number    int    `yaml:"number"`
+	Played    string `yaml:"played"` // real-world date, YYYY-MM-DD
+	Calendar  string `yaml:"calendar"`
+	Start     string `yaml:"start"` // AAYYYY-MM-DD
+	StartDays int    `yaml:"start_days"`
+	End       string `yaml:"end"` // AAYYYY-MM-DD
+	EndDays   int    `yaml:"end_days"`
+	Players   string `yaml:"players,omitempty"`
+	Event     string `yaml:"event,omitempty"` // ID of the session-recap event
+}
+
+// Party is a group of players with its own place in time, for campaigns
+// where parties' in-world dates drift apart.
+type Party struct {
+	Name        string `yaml:"name"`
+	Calendar    string `yaml:"calendar"`
+	CurrentDate string `yaml:"current_date"` // AAYYYY-MM-DD
+}
+
+// Countdown is a deadline counted down on the campaign clock, due either
+// on a date or when an event takes place.
+type Countdown struct {
+	Name     string `yaml:"name"`
+	Calendar string `yaml:"calendar"`
+	Target   string `yaml:"target,omitempty"` // AAYYYY-MM-DD
+	Event    string `yaml:"event,omitempty"`  // ID of the event it counts down to
+	WarnDays int    `yaml:"warn_days,omitempty"`
+}
+
+// EncounterTable is a random encounter table, one per file in the
+// encounters directory.
+type EncounterTable struct {
+	Name     string           `yaml:"name"`
+	Calendar string           `yaml:"calendar,omitempty"` // calendar to roll in, when there are several
+	Location string           `yaml:"location,omitempty"` // usual place the table is rolled for
+	Entries  []EncounterEntry `yaml:"entries"`
+	Path     string           `yaml:"-"`
+}
+
+// EncounterEntry is one possible result of an encounter table.
+type EncounterEntry struct {
+	Result    string              `yaml:"result"`
+	Weight    float64             `yaml:"weight,omitempty"` // defaults to 1
+	Modifiers []EncounterModifier `yaml:"modifiers,omitempty"`
+}
+
+// EncounterModifier scales an entry's weight when every condition it sets
+// holds.
+type EncounterModifier struct {
+	Season     string  `yaml:"season,omitempty"`
+	Time       string  `yaml:"time,omitempty"`     // time of day, e.g. night
+	Location   string  `yaml:"location,omitempty"` // the place or anywhere inside it
+	Moon       string  `yaml:"moon,omitempty"`     // a phase any moon is in, or "Moon: phase"
+	Multiplier float64 `yaml:"multiplier"`         // 0 rules the entry out
+}
+
+// HistoryEntry is a recorded operation, holding the workspace files it
+// changed so it can be undone and redone.
+type HistoryEntry struct {
+	ID      int          `yaml:"id"`
+	Time    string       `yaml:"time"` // real-world time, RFC 3339
+	Action  string       `yaml:"action"`
+	Reason  string       `yaml:"reason,omitempty"`
+	Undone  bool         `yaml:"undone,omitempty"`
+	Changes []FileChange `yaml:"changes"`
+}
+
+// FileChange is a file's contents before and after an operation. A nil
+// side means the file didn't exist.
+type FileChange struct {
+	Path   string  `yaml:"path"`
+	Before *string `yaml:"before,omitempty"`
+	After  *string `yaml:"after,omitempty"`
 }
 
 // CreateCalendarInput holds data for calendar creation
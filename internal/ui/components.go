@@ -2,6 +2,8 @@ package ui
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -49,6 +51,33 @@ func MainMenuItems() []list.Item {
 	return []list.Item{
 		Item{Title: "Create Calendar", Description: "Create a new fantasy calendar"},
 		Item{Title: "Create Event", Description: "Add an event to an existing calendar"},
+		Item{Title: "Events On Date", Description: "List the events taking place on a date"},
+		Item{Title: "Search Events", Description: "Full-text search across event names and notes"},
+		Item{Title: "Filter By Tag", Description: "Only list events carrying the given tags"},
+		Item{Title: "Toggle Player View", Description: "Hide GM-only events and sections everywhere"},
+		Item{Title: "Export Timeline", Description: "Write every listed event to a markdown timeline"},
+		Item{Title: "Add Character", Description: "Add a character with birth and death dates"},
+		Item{Title: "Characters", Description: "Browse characters and look up their ages"},
+		Item{Title: "Record Knowledge", Description: "Note when a character learned of an event"},
+		Item{Title: "Character Knowledge", Description: "List and export what a character knows"},
+		Item{Title: "Add Location", Description: "Add a continent, region, city or building"},
+		Item{Title: "Locations", Description: "Browse places and the events held there"},
+		Item{Title: "Campaign Clock", Description: "View or advance the current in-world date"},
+		Item{Title: "Set Event Status", Description: "Flag an event as scheduled or occurred"},
+		Item{Title: "Log Session", Description: "Record a game session and create its recap event"},
+		Item{Title: "Session Timeline", Description: "List sessions alongside in-world events"},
+		Item{Title: "Add Countdown", Description: "Track a deadline on a date or event"},
+		Item{Title: "Countdowns", Description: "Days left on every deadline by the campaign clock"},
+		Item{Title: "Add Party", Description: "Add a party with its own place in time"},
+		Item{Title: "Parties", Description: "See where each party is in time and advance its clock"},
+		Item{Title: "Select Party", Description: "Choose the party new events happen to"},
+		Item{Title: "Party Catch-Up", Description: "List what happened between two parties' dates"},
+		Item{Title: "Travel Calculator", Description: "Work out when a journey arrives"},
+		Item{Title: "Roll Encounter", Description: "Roll on an encounter table for the current date"},
+		Item{Title: "History", Description: "Undo or redo recent clock changes and edits"},
+		Item{Title: "Check Consistency", Description: "Find continuity mistakes across the timeline"},
+		Item{Title: "Import Events", Description: "Create events from a CSV or JSON file"},
+		Item{Title: "Migrate Event Files", Description: "Move event files into the configured layout"},
 		Item{Title: "View Calendars", Description: "View all configured calendars"},
 		Item{Title: "Exit", Description: "Exit the application"},
 	}
@@ -65,3 +94,230 @@ func CalendarListItems(calendars []config.Calendar) []list.Item {
 	}
 	return items
 }
+
+// EncounterTableListItems creates list items from encounter tables
+func EncounterTableListItems(tables []config.EncounterTable) []list.Item {
+	items := make([]list.Item, len(tables))
+	for i, table := range tables {
+		desc := fmt.Sprintf("%d entries", len(table.Entries))
+		if table.Location != "" {
+			desc += ", " + table.Location
+		}
+		if table.Calendar != "" {
+			desc += ", " + table.Calendar
+		}
+		items[i] = Item{Title: table.Name, Description: desc}
+	}
+	return items
+}
+
+// TemplateListItems creates list items from event templates
+func TemplateListItems(templates []config.EventTemplate) []list.Item {
+	items := make([]list.Item, len(templates))
+	for i, tmpl := range templates {
+		desc := "No extra fields"
+		if len(tmpl.Fields) > 0 {
+			names := make([]string, len(tmpl.Fields))
+			for j, field := range tmpl.Fields {
+				names[j] = field.Name
+			}
+			desc = "Asks for " + strings.Join(names, ", ")
+		}
+		items[i] = Item{Title: tmpl.Name, Description: desc}
+	}
+	return items
+}
+
+// EventListItems creates list items from events
+func EventListItems(events []config.Event) []list.Item {
+	items := make([]list.Item, len(events))
+	for i, event := range events {
+		desc := event.DateDescription()
+		if event.IsRanged() {
+			desc = fmt.Sprintf("%s to %s (%d days)", desc, event.EndDateString(), event.Length())
+		}
+		if event.Recurrence != nil {
+			desc = fmt.Sprintf("%s, repeats %s", desc, event.Recurrence)
+		}
+		if event.Party != "" {
+			desc = fmt.Sprintf("%s, %s", desc, event.Party)
+		}
+		if event.Status == config.StatusScheduled {
+			desc = fmt.Sprintf("%s (scheduled)", desc)
+		}
+		if event.Category != "" {
+			desc = fmt.Sprintf("%s [%s]", desc, RenderCategory(event.Category))
+		}
+		if len(event.Tags) > 0 {
+			desc = fmt.Sprintf("%s #%s", desc, strings.Join(event.Tags, " #"))
+		}
+		items[i] = Item{
+			Title:       event.Name,
+			Description: desc,
+		}
+	}
+	return items
+}
+
+// SearchResultItems creates list items from search matches, showing each
+// event's date and snippet
+func SearchResultItems(events []config.Event, snippets []string) []list.Item {
+	items := make([]list.Item, len(events))
+	for i, event := range events {
+		desc := event.DateDescription()
+		if snippets[i] != "" {
+			desc = fmt.Sprintf("%s: %s", desc, snippets[i])
+		}
+		items[i] = Item{
+			Title:       event.Name,
+			Description: desc,
+		}
+	}
+	return items
+}
+
+// IssueListItems creates list items from consistency issues, one per
+// event, kind and message
+func IssueListItems(events []config.Event, kinds, messages []string) []list.Item {
+	items := make([]list.Item, len(events))
+	for i, event := range events {
+		items[i] = Item{
+			Title:       fmt.Sprintf("%s (%s)", event.Name, event.DateString()),
+			Description: fmt.Sprintf("%s: %s", kinds[i], messages[i]),
+		}
+	}
+	return items
+}
+
+// DigestListItems creates list items from the entries of a clock digest,
+// one per date, kind and message
+func DigestListItems(dates, kinds, messages []string) []list.Item {
+	items := make([]list.Item, len(dates))
+	for i := range dates {
+		items[i] = Item{
+			Title:       messages[i],
+			Description: fmt.Sprintf("%s (%s)", dates[i], kinds[i]),
+		}
+	}
+	return items
+}
+
+// TimelineListItems creates list items for the unified timeline. Entries
+// with a session are shown as sessions, the rest as events.
+func TimelineListItems(events []config.Event, sessions []*config.Session) []list.Item {
+	items := make([]list.Item, len(events))
+	for i, event := range events {
+		session := sessions[i]
+		if session == nil {
+			items[i] = EventListItems([]config.Event{event})[0]
+			continue
+		}
+
+		desc := session.Start
+		if session.End != session.Start {
+			desc = fmt.Sprintf("%s to %s", session.Start, session.End)
+		}
+		items[i] = Item{
+			Title:       fmt.Sprintf("Session %d (played %s)", session.Number, session.Played),
+			Description: fmt.Sprintf("%s [%s]", desc, RenderCategory("session")),
+		}
+	}
+	return items
+}
+
+// HistoryListItems creates list items from history entries, showing when
+// each was made, why, and whether it has been undone
+func HistoryListItems(entries []config.HistoryEntry) []list.Item {
+	items := make([]list.Item, len(entries))
+	for i, entry := range entries {
+		desc := entry.Time
+		if t, err := time.Parse(time.RFC3339, entry.Time); err == nil {
+			desc = t.Format("2006-01-02 15:04")
+		}
+		if entry.Reason != "" {
+			desc += " - " + entry.Reason
+		}
+		desc += fmt.Sprintf(" (%d files)", len(entry.Changes))
+
+		title := entry.Action
+		if entry.Undone {
+			title = RenderMuted(title + " (undone)")
+		}
+		items[i] = Item{Title: title, Description: desc}
+	}
+	return items
+}
+
+// PartyListItems creates list items from parties, one per description of
+// where the party is in time
+func PartyListItems(parties []config.Party, dates []string) []list.Item {
+	items := make([]list.Item, len(parties))
+	for i, party := range parties {
+		items[i] = Item{
+			Title:       party.Name,
+			Description: fmt.Sprintf("%s (%s)", dates[i], party.Calendar),
+		}
+	}
+	return items
+}
+
+// CountdownListItems creates list items from countdowns, one per state,
+// days remaining and target date. Deadlines due or passed stand out, and
+// those within their warning days are highlighted.
+func CountdownListItems(countdowns []config.Countdown, states, remaining, targets []string) []list.Item {
+	items := make([]list.Item, len(countdowns))
+	for i, c := range countdowns {
+		left := remaining[i]
+		switch states[i] {
+		case "due", "passed":
+			left = RenderError(left)
+		case "near":
+			left = RenderHighlight(left)
+		case "unknown":
+			left = RenderMuted(left)
+		}
+
+		desc := left
+		if targets[i] != "" {
+			desc = fmt.Sprintf("%s, %s (%s)", left, targets[i], c.Calendar)
+		}
+		items[i] = Item{Title: c.Name, Description: desc}
+	}
+	return items
+}
+
+// CharacterListItems creates list items from characters
+func CharacterListItems(characters []config.Character) []list.Item {
+	items := make([]list.Item, len(characters))
+	for i, ch := range characters {
+		desc := "Born " + ch.Born
+		if ch.Died != "" {
+			desc += ", died " + ch.Died
+		}
+		if ch.Species != "" {
+			desc = fmt.Sprintf("%s, %s", ch.Species, desc)
+		}
+		items[i] = Item{
+			Title:       ch.Name,
+			Description: desc,
+		}
+	}
+	return items
+}
+
+// LocationListItems creates list items from locations, indenting each by
+// its depth in the hierarchy
+func LocationListItems(locations []config.Location, depths []int) []list.Item {
+	items := make([]list.Item, len(locations))
+	for i, loc := range locations {
+		desc := loc.Kind
+		if loc.Parent != "" {
+			desc = strings.TrimSpace(fmt.Sprintf("%s in %s", loc.Kind, loc.Parent))
+		}
+		items[i] = Item{
+			Title:       strings.Repeat("  ", depths[i]) + loc.Name,
+			Description: desc,
+		}
+	}
+	return items
+}
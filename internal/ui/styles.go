@@ -13,8 +13,21 @@ const (
 	colorSuccess   = "#00FF00"
 	colorText      = "#FAFAFA"
 	colorMuted     = "#626262"
+	colorHighlight = "#FFD866"
 )
 
+// Event category colors, keyed by category name
+var categoryColors = map[string]string{
+	"battle":  "#E5484D",
+	"birth":   "#46A758",
+	"death":   "#8E8C99",
+	"session": "#3E63DD",
+	"rumour":  "#F5D90A",
+}
+
+// Fallback color for categories without their own entry
+const colorCategoryDefault = "#AD7FA8"
+
 // Exported styles for use in the application
 var (
 	// AppStyle is the main container style
@@ -54,8 +67,25 @@ var (
 	RenderMuted = lipgloss.NewStyle().
 			Foreground(lipgloss.Color(colorMuted)).
 			Render
+
+	// RenderHighlight returns text styled as a search match
+	RenderHighlight = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color(colorHighlight)).
+			Render
 )
 
+// RenderCategory returns the category name in its configured color
+func RenderCategory(category string) string {
+	color, ok := categoryColors[category]
+	if !ok {
+		color = colorCategoryDefault
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color(color)).
+		Render(category)
+}
+
 // ListStyles returns styles for list components
 func ListStyles() (lipgloss.Style, lipgloss.Style, lipgloss.Style) {
 	listTitle := TitleStyle.Copy()